@@ -0,0 +1,204 @@
+package softlinePayment
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultAsyncTimeout   = 30 * time.Minute
+	defaultAsyncPollAfter = 30 * time.Second
+)
+
+// AsyncPaymentOptions задаёт, как CreatePaymentAsync дожидается окончательного статуса.
+type AsyncPaymentOptions struct {
+	// общее время ожидания; 0 — 30 минут
+	Timeout time.Duration
+	// сколько ждать колбэк до начала опроса PostCheck; 0 — 30 секунд
+	PollAfter time.Duration
+	// не опрашивать SOM, ждать только NotifyPaymentStatus
+	DisablePolling bool
+	Poll           PollOptions
+	// вызывается один раз по завершении ожидания, в отдельной горутине
+	OnComplete func(payment *PaymentResp, err error)
+}
+
+// PendingPayment — созданный платёж, окончательный статус которого ещё не известен.
+type PendingPayment struct {
+	Response *CreatePaymentResp
+
+	notify  chan *PaymentResp
+	done    chan struct{}
+	cancel  context.CancelFunc
+	payment *PaymentResp
+	err     error
+}
+
+// Done закрывается, когда получен окончательный статус или ожидание прервано.
+func (p *PendingPayment) Done() <-chan struct{} {
+	return p.done
+}
+
+// Result возвращает итог ожидания; до закрытия Done оба значения nil.
+func (p *PendingPayment) Result() (*PaymentResp, error) {
+	select {
+	case <-p.done:
+		return p.payment, p.err
+	default:
+		return nil, nil
+	}
+}
+
+// Wait блокируется до окончательного статуса или отмены ctx. Отмена ctx не останавливает
+// само ожидание — для этого есть Cancel.
+func (p *PendingPayment) Wait(ctx context.Context) (*PaymentResp, error) {
+	select {
+	case <-p.done:
+		return p.payment, p.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Cancel прекращает ожидание; Result вернёт context.Canceled.
+func (p *PendingPayment) Cancel() {
+	p.cancel()
+}
+
+// paymentWaiters — ожидающие окончательного статуса заказы.
+type paymentWaiters struct {
+	mu      sync.Mutex
+	pending map[int64][]*PendingPayment
+}
+
+func newPaymentWaiters() *paymentWaiters {
+	return &paymentWaiters{pending: make(map[int64][]*PendingPayment)}
+}
+
+func (w *paymentWaiters) add(orderID int64, p *PendingPayment) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pending[orderID] = append(w.pending[orderID], p)
+}
+
+func (w *paymentWaiters) remove(orderID int64, p *PendingPayment) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	list := w.pending[orderID]
+	for i, item := range list {
+		if item == p {
+			list = append(list[:i], list[i+1:]...)
+			break
+		}
+	}
+	if len(list) == 0 {
+		delete(w.pending, orderID)
+		return
+	}
+	w.pending[orderID] = list
+}
+
+func (w *paymentWaiters) notify(payment *PaymentResp) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, p := range w.pending[payment.OrderId] {
+		select {
+		case p.notify <- payment:
+		default:
+		}
+	}
+}
+
+// NotifyPaymentStatus передаёт статус из колбэка ожидающим CreatePaymentAsync.
+// Неокончательные статусы игнорируются. webhook.Handler вызывает его сам, если задан Notifier.
+func (s *Service) NotifyPaymentStatus(payment *PaymentResp) {
+	if payment == nil || !payment.Status.IsTerminal() {
+		return
+	}
+	s.events.Publish(LifecycleEvent{Type: EventPaymentCompleted, Payment: payment})
+	s.waiters.notify(payment)
+}
+
+// CreatePaymentAsync создаёт платёж и сразу возвращается, не дожидаясь оплаты.
+// Окончательный статус приходит через NotifyPaymentStatus (колбэк SOM), а если его нет
+// дольше PollAfter — опросом PostCheck. При ошибке создания pending равен nil.
+func (s *Service) CreatePaymentAsync(ctx context.Context, data CreatePaymentReq, token string, async AsyncPaymentOptions, opts ...RequestOption) (respBody []byte, pending *PendingPayment, err error) {
+	respBody, response, err := s.CreatePayment(ctx, data, token, opts...)
+	if err != nil {
+		return respBody, nil, err
+	}
+
+	if async.Timeout <= 0 {
+		async.Timeout = defaultAsyncTimeout
+	}
+	if async.PollAfter <= 0 {
+		async.PollAfter = defaultAsyncPollAfter
+	}
+	if response.Status.IsTerminal() {
+		// SOM ответил окончательным статусом сразу, колбэк ждать незачем
+		async.PollAfter = 0
+	}
+
+	// ожидание переживает ctx вызова, но прерывается при Close
+	waitCtx, cancel := context.WithTimeout(s.shutdown.ctx, async.Timeout)
+	pending = &PendingPayment{
+		Response: response,
+		notify:   make(chan *PaymentResp, 1),
+		done:     make(chan struct{}),
+		cancel:   cancel,
+	}
+	s.waiters.add(response.OrderId, pending)
+
+	// Close дожидается завершения ожидания; после Close waitCtx уже отменён
+	waitCtx, done, _ := s.shutdown.begin(waitCtx)
+	go func() {
+		defer done()
+		s.watchPayment(waitCtx, pending, async)
+	}()
+
+	return respBody, pending, nil
+}
+
+func (s *Service) watchPayment(ctx context.Context, p *PendingPayment, async AsyncPaymentOptions) {
+	orderID := p.Response.OrderId
+	defer p.cancel()
+	defer s.waiters.remove(orderID, p)
+
+	var pollAfter <-chan time.Time
+	if !async.DisablePolling {
+		timer := time.NewTimer(async.PollAfter)
+		defer timer.Stop()
+		pollAfter = timer.C
+	}
+
+	type pollResult struct {
+		payment *PaymentResp
+		err     error
+	}
+	polled := make(chan pollResult, 1)
+
+	select {
+	case payment := <-p.notify:
+		p.payment = payment
+	case <-pollAfter:
+		go func() {
+			payment, err := s.WaitForPaymentStatus(ctx, fmt.Sprint(orderID), nil, async.Poll)
+			polled <- pollResult{payment, err}
+		}()
+		select {
+		case payment := <-p.notify:
+			p.payment = payment
+		case result := <-polled:
+			p.payment, p.err = result.payment, result.err
+		}
+	case <-ctx.Done():
+		p.err = fmt.Errorf("order %d did not reach terminal status: %w", orderID, ctx.Err())
+	}
+	close(p.done)
+
+	if async.OnComplete != nil {
+		async.OnComplete(p.payment, p.err)
+	}
+}
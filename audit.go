@@ -0,0 +1,115 @@
+package softlinePayment
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// AuditRecord — обезличенная запись об одном вызове SOM для журнала аудита.
+// В неё не попадают токены, учётные данные, данные карты и тела запросов.
+type AuditRecord struct {
+	Time       time.Time
+	Operation  string
+	Method     string
+	Path       string
+	RequestID  string
+	OrderID    string
+	Amount     string
+	Currency   string
+	Status     string
+	HTTPStatus int
+	Duration   time.Duration
+	Err        error
+}
+
+// AuditHook получает запись о каждом вызове SOM и его результате.
+// Вызывается синхронно после ответа, поэтому медленную запись стоит выносить в фон.
+type AuditHook interface {
+	Audit(ctx context.Context, record AuditRecord)
+}
+
+// AuditFunc позволяет использовать обычную функцию как AuditHook.
+type AuditFunc func(ctx context.Context, record AuditRecord)
+
+func (f AuditFunc) Audit(ctx context.Context, record AuditRecord) {
+	f(ctx, record)
+}
+
+// WithAuditHook подключает журнал аудита вызовов SOM.
+func WithAuditHook(hook AuditHook) Option {
+	return func(s *Service) {
+		s.audit = hook
+	}
+}
+
+func (s *Service) auditCall(ctx context.Context, inputs *SendParams, request, response any, started time.Time, err error) {
+	if s.audit == nil {
+		return
+	}
+
+	record := AuditRecord{
+		Time:       started,
+		Operation:  inputs.Operation,
+		Method:     inputs.HttpMethod,
+		Path:       inputs.Path,
+		RequestID:  inputs.Meta.RequestID,
+		HTTPStatus: inputs.HttpCode,
+		Duration:   time.Since(started),
+		Err:        err,
+	}
+	if record.RequestID == "" {
+		record.RequestID = inputs.RequestID
+	}
+	auditRequest(&record, request)
+	auditResponse(&record, response)
+
+	s.audit.Audit(ctx, record)
+}
+
+// auditRequest переносит в запись только безопасные поля известных запросов.
+func auditRequest(record *AuditRecord, request any) {
+	switch r := request.(type) {
+	case *CreatePaymentReq:
+		record.Amount, record.Currency = r.Amount.String(), r.Currency
+	case *MakePaymentReq:
+		record.OrderID = strconv.FormatInt(r.ParentOrderId, 10)
+		record.Amount, record.Currency = r.Amount.String(), r.Currency
+	case *RefundReq:
+		record.OrderID = r.OrderID
+	case *PartialRefundReq:
+		record.OrderID = r.OrderID
+		record.Amount, record.Currency = r.Amount.String(), r.Currency
+	case *CaptureReq:
+		record.OrderID = r.OrderID
+		if r.Amount != nil {
+			record.Amount = r.Amount.String()
+		}
+	case *CancelReq:
+		record.OrderID = r.OrderID
+	}
+}
+
+func auditResponse(record *AuditRecord, response any) {
+	switch r := response.(type) {
+	case *CreatePaymentResp:
+		if r.OrderId != 0 {
+			record.OrderID = strconv.FormatInt(r.OrderId, 10)
+		}
+		record.Status = string(r.Status)
+	case *PaymentResp:
+		if r.OrderId != 0 {
+			record.OrderID = strconv.FormatInt(r.OrderId, 10)
+		}
+		record.Status = string(r.Status)
+		if record.Currency == "" {
+			record.Currency = r.Currency
+		}
+	case *RefundResp:
+		if r.OrderId != 0 {
+			record.OrderID = strconv.FormatInt(r.OrderId, 10)
+		}
+		record.Status = r.Status
+		record.Amount, record.Currency = r.Amount.String(), r.Currency
+	}
+}
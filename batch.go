@@ -0,0 +1,113 @@
+package softlinePayment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const defaultBatchParallelism = 8
+
+// PaymentResult — результат получения одного заказа в пакетном запросе.
+type PaymentResult struct {
+	OrderID string
+	Payment *PaymentResp
+	Err     error
+}
+
+// GetPayments параллельно получает статусы заказов, одновременно выполняя не более parallelism запросов.
+// Результаты возвращаются в порядке orderIDs; ошибка объединяет ошибки всех неудавшихся заказов.
+func (s *Service) GetPayments(ctx context.Context, orderIDs []string, parallelism int) ([]PaymentResult, error) {
+	if parallelism < 1 {
+		parallelism = defaultBatchParallelism
+	}
+
+	results := make([]PaymentResult, len(orderIDs))
+	sem := make(chan struct{}, parallelism)
+
+	var wg sync.WaitGroup
+	for i, orderID := range orderIDs {
+		results[i].OrderID = orderID
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, orderID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			_, results[i].Payment, results[i].Err = s.PostCheck(ctx, orderID, "", WithoutCache())
+		}(i, orderID)
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("order %s: %w", result.OrderID, result.Err))
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// RefundResult — результат одного возврата в RefundBatch.
+type RefundResult struct {
+	OrderID        string
+	IdempotencyKey string
+	Payment        *PaymentResp
+	Err            error
+}
+
+// RefundBatch параллельно выполняет возвраты, одновременно — не более parallelism запросов.
+// Каждому запросу без IdempotencyKey назначается свой ключ; он возвращается в результате,
+// чтобы неудавшиеся возвраты можно было повторить без риска двойного списания.
+// Результаты возвращаются в порядке requests; ошибка объединяет ошибки всех неудавшихся возвратов.
+func (s *Service) RefundBatch(ctx context.Context, requests []RefundReq, parallelism int) ([]RefundResult, error) {
+	if parallelism < 1 {
+		parallelism = defaultBatchParallelism
+	}
+
+	results := make([]RefundResult, len(requests))
+	sem := make(chan struct{}, parallelism)
+
+	var wg sync.WaitGroup
+	for i, request := range requests {
+		if request.IdempotencyKey == "" {
+			request.IdempotencyKey = newUUID()
+		}
+		results[i].OrderID = request.OrderID
+		results[i].IdempotencyKey = request.IdempotencyKey
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, request RefundReq) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i].Payment, results[i].Err = s.Refund(ctx, request, "")
+		}(i, request)
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("refund of order %s: %w", result.OrderID, result.Err))
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,47 @@
+package softlinePayment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const binLookup = "/v1/bin/%s"
+
+// LookupBIN возвращает бренд карты, страну эмитента и способы оплаты, доступные
+// для суммы и валюты из запроса. Передаётся только BIN (6–8 первых цифр), не номер карты.
+func (s *Service) LookupBIN(ctx context.Context, request BINLookupReq, token string, opts ...RequestOption) (respBody []byte, response *BINInfo, err error) {
+	if err = validateBIN(request.BIN); err != nil {
+		return nil, new(BINInfo), err
+	}
+
+	query := queryParams{}.Set("currency", request.Currency)
+	if !request.Amount.IsZero() {
+		query.Set("amount", request.Amount.String())
+	}
+
+	path, err := endpointPath(binLookup, request.BIN)
+	if err != nil {
+		return nil, new(BINInfo), err
+	}
+
+	return call[struct{}, BINInfo](ctx, s, &SendParams{
+		Operation:   "lookup_bin",
+		Path:        path,
+		HttpMethod:  http.MethodGet,
+		AuthNeed:    true,
+		QueryParams: query,
+	}, nil, token, opts)
+}
+
+func validateBIN(bin string) error {
+	if len(bin) < 6 || len(bin) > 8 {
+		return fmt.Errorf("%w: BIN must be 6 to 8 digits, got %d characters", ErrValidation, len(bin))
+	}
+	for _, r := range bin {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("%w: BIN must contain only digits", ErrValidation)
+		}
+	}
+	return nil
+}
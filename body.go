@@ -0,0 +1,114 @@
+package softlinePayment
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const defaultMaxResponseBytes = 10 << 20
+
+var ErrResponseTooLarge = errors.New("softline: response body exceeds size limit")
+
+// readResponseBody читает тело ответа, распаковывая gzip и ограничивая размер уже распакованных данных.
+func readResponseBody(resp *http.Response, limit int64) ([]byte, error) {
+	var reader io.Reader = resp.Body
+
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("can't open gzip body: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	if limit <= 0 {
+		limit = defaultMaxResponseBytes
+	}
+
+	body, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return body, err
+	}
+	if int64(len(body)) > limit {
+		return body[:limit], fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, limit)
+	}
+
+	return body, nil
+}
+
+var ErrBodyNotReplayable = errors.New("softline: streamed request body can't be sent again")
+
+// requestBody — тело запроса: буфер, который можно отправлять сколько угодно раз,
+// или поток (SendParams.Stream), который отправляется повторно, только если реализует io.Seeker.
+type requestBody struct {
+	buf    []byte
+	stream io.Reader
+	length int64
+	sent   bool
+}
+
+func newRequestBody(inputs *SendParams) (*requestBody, error) {
+	if inputs.Body == nil {
+		return nil, nil
+	}
+
+	if inputs.Stream {
+		return &requestBody{stream: inputs.Body, length: inputs.ContentLength}, nil
+	}
+
+	buf, err := io.ReadAll(inputs.Body)
+	if err != nil {
+		return nil, fmt.Errorf("can't read request body! Err: %w", err)
+	}
+	return &requestBody{buf: buf, length: int64(len(buf))}, nil
+}
+
+// replayable — тело можно отправить ещё раз: при повторе, переключении хоста или после 401.
+func (b *requestBody) replayable() bool {
+	if b == nil || b.stream == nil {
+		return true
+	}
+	_, ok := b.stream.(io.Seeker)
+	return ok
+}
+
+// reader возвращает тело для очередной отправки и его длину; 0 — длина неизвестна, тело уйдёт чанками.
+func (b *requestBody) reader() (io.Reader, int64, error) {
+	if b == nil {
+		return nil, 0, nil
+	}
+	if b.stream == nil {
+		return bytes.NewReader(b.buf), b.length, nil
+	}
+
+	if b.sent {
+		seeker, ok := b.stream.(io.Seeker)
+		if !ok {
+			return nil, 0, ErrBodyNotReplayable
+		}
+		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+			return nil, 0, fmt.Errorf("can't rewind request body: %w", err)
+		}
+	}
+	b.sent = true
+
+	// io.NopCloser скрывает тип, чтобы http.NewRequest не пытался определить длину сам
+	return io.NopCloser(b.stream), b.length, nil
+}
+
+// debugString — тело для отладочного лога; потоковое тело не читается, чтобы не сломать отправку.
+func (b *requestBody) debugString() string {
+	switch {
+	case b == nil:
+		return ""
+	case b.stream != nil:
+		return fmt.Sprintf("<stream, %d bytes>", b.length)
+	}
+	return redactBody(b.buf)
+}
@@ -0,0 +1,146 @@
+package softlinePayment
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("softline: circuit breaker is open")
+
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (c CircuitState) String() string {
+	switch c {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerSettings — параметры размыкателя цепи.
+type CircuitBreakerSettings struct {
+	// число подряд идущих сбоев, после которого цепь размыкается
+	FailureThreshold int
+	// сколько цепь остаётся разомкнутой до пробного запроса
+	OpenTimeout time.Duration
+	// сколько пробных запросов пропускается в полуоткрытом состоянии
+	HalfOpenMaxRequests int
+	OnStateChange       func(from, to CircuitState)
+}
+
+// WithCircuitBreaker включает размыкатель цепи: при недоступности SOM запросы падают сразу с ErrCircuitOpen.
+func WithCircuitBreaker(settings CircuitBreakerSettings) Option {
+	return func(s *Service) {
+		s.breaker = NewCircuitBreaker(settings)
+	}
+}
+
+type CircuitBreaker struct {
+	mu       sync.Mutex
+	settings CircuitBreakerSettings
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probes   int
+	events   *EventBus
+}
+
+func NewCircuitBreaker(settings CircuitBreakerSettings) *CircuitBreaker {
+	if settings.FailureThreshold < 1 {
+		settings.FailureThreshold = 5
+	}
+	if settings.OpenTimeout <= 0 {
+		settings.OpenTimeout = 30 * time.Second
+	}
+	if settings.HalfOpenMaxRequests < 1 {
+		settings.HalfOpenMaxRequests = 1
+	}
+	return &CircuitBreaker{
+		settings: settings,
+	}
+}
+
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// Allow сообщает, можно ли выполнить запрос.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.settings.OpenTimeout {
+			return ErrCircuitOpen
+		}
+		b.setState(CircuitHalfOpen)
+		b.probes = 1
+		return nil
+	case CircuitHalfOpen:
+		if b.probes >= b.settings.HalfOpenMaxRequests {
+			return ErrCircuitOpen
+		}
+		b.probes++
+	}
+	return nil
+}
+
+// Record учитывает результат запроса, пропущенного Allow.
+func (b *CircuitBreaker) Record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if success {
+		b.failures = 0
+		if b.state != CircuitClosed {
+			b.setState(CircuitClosed)
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.settings.FailureThreshold {
+		b.openedAt = time.Now()
+		if b.state != CircuitOpen {
+			b.setState(CircuitOpen)
+		}
+	}
+}
+
+// Cancel возвращает слот пробного запроса, пропущенного Allow, если запрос отменён
+// до результата: иначе полуоткрытая цепь так и осталась бы без свободных проб.
+func (b *CircuitBreaker) Cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitHalfOpen && b.probes > 0 {
+		b.probes--
+	}
+}
+
+func (b *CircuitBreaker) setState(state CircuitState) {
+	from := b.state
+	b.state = state
+	if b.settings.OnStateChange != nil {
+		// колбэк вызываем асинхронно, чтобы он не мог заблокировать breaker
+		go b.settings.OnStateChange(from, state)
+	}
+	if b.events != nil {
+		go b.events.Publish(LifecycleEvent{Type: EventCircuitChanged, CircuitFrom: from, CircuitTo: state})
+	}
+}
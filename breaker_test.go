@@ -0,0 +1,131 @@
+package softlinePayment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerSettings{FailureThreshold: 2, OpenTimeout: time.Hour})
+
+	for i := 0; i < 2; i++ {
+		if err := b.Allow(); err != nil {
+			t.Fatalf("Allow %d: %v", i, err)
+		}
+		b.Record(false)
+	}
+	if state := b.State(); state != CircuitOpen {
+		t.Fatalf("state = %s, want open", state)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Allow = %v, want %v", err, ErrCircuitOpen)
+	}
+}
+
+func TestCircuitBreakerSuccessResetsFailures(t *testing.T) {
+	b := NewCircuitBreaker(CircuitBreakerSettings{FailureThreshold: 2, OpenTimeout: time.Hour})
+
+	b.Record(false)
+	b.Record(true)
+	b.Record(false)
+	if state := b.State(); state != CircuitClosed {
+		t.Fatalf("state = %s, want closed", state)
+	}
+}
+
+func openBreaker(t *testing.T, maxProbes int) *CircuitBreaker {
+	t.Helper()
+
+	b := NewCircuitBreaker(CircuitBreakerSettings{FailureThreshold: 1, OpenTimeout: time.Millisecond, HalfOpenMaxRequests: maxProbes})
+	b.Record(false)
+	time.Sleep(5 * time.Millisecond)
+	return b
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	b := openBreaker(t, 1)
+
+	if err := b.Allow(); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if state := b.State(); state != CircuitHalfOpen {
+		t.Fatalf("state = %s, want half-open", state)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second probe = %v, want %v", err, ErrCircuitOpen)
+	}
+
+	b.Record(true)
+	if state := b.State(); state != CircuitClosed {
+		t.Fatalf("state after successful probe = %s, want closed", state)
+	}
+}
+
+func TestCircuitBreakerHalfOpenFailureReopens(t *testing.T) {
+	b := openBreaker(t, 1)
+
+	if err := b.Allow(); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	b.Record(false)
+	if state := b.State(); state != CircuitOpen {
+		t.Fatalf("state after failed probe = %s, want open", state)
+	}
+}
+
+func TestCircuitBreakerCancelledProbe(t *testing.T) {
+	b := openBreaker(t, 1)
+
+	if err := b.Allow(); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	// проба отменена до ответа: слот должен вернуться
+	b.Cancel()
+
+	if err := b.Allow(); err != nil {
+		t.Fatalf("probe after cancel: %v", err)
+	}
+	b.Record(true)
+	if state := b.State(); state != CircuitClosed {
+		t.Fatalf("state = %s, want closed", state)
+	}
+}
+
+func TestServiceCancelledProbeReleasesBreaker(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"paid"}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	s, err := New(&Config{URI: server.URL, Login: "login", Pass: "pass"},
+		WithCircuitBreaker(CircuitBreakerSettings{FailureThreshold: 1, OpenTimeout: time.Millisecond}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	s.breaker.Record(false)
+	time.Sleep(5 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	if _, _, err = s.PostCheck(ctx, "42", "token"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("PostCheck = %v, want %v", err, context.Canceled)
+	}
+
+	if err = s.breaker.Allow(); err != nil {
+		t.Fatalf("breaker after cancelled probe: %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package softlinePayment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// ResponseCache хранит тела успешных GET-ответов SOM.
+type ResponseCache interface {
+	Get(ctx context.Context, key string) (body []byte, ok bool, err error)
+	Set(ctx context.Context, key string, body []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// CacheOptions — настройки кэша GET-запросов.
+type CacheOptions struct {
+	// хранилище ответов; nil — MemoryResponseCache
+	Store ResponseCache
+	// TTL для GET-операций, не перечисленных в TTLByOperation; 0 — кэшировать только их
+	DefaultTTL time.Duration
+	// TTL по имени операции (например, "post_check"); 0 — не кэшировать операцию
+	TTLByOperation map[string]time.Duration
+}
+
+// WithResponseCache включает кэш GET-запросов. Изменяющий запрос по заказу
+// (возврат, списание, отмена) сбрасывает закэшированный статус этого заказа.
+// Внутренние проверки статуса (защита от двойного возврата, ожидание статуса, 3DS, GetPayments) кэш не используют.
+func WithResponseCache(options CacheOptions) Option {
+	return func(s *Service) {
+		if options.Store == nil {
+			options.Store = NewMemoryResponseCache()
+		}
+		s.cache = &options
+	}
+}
+
+// WithoutCache запрашивает SOM в обход кэша; свежий ответ всё равно попадёт в кэш.
+func WithoutCache() RequestOption {
+	return func(inputs *SendParams) {
+		inputs.NoCache = true
+	}
+}
+
+func (o *CacheOptions) ttl(operation string) time.Duration {
+	if ttl, ok := o.TTLByOperation[operation]; ok {
+		return ttl
+	}
+	return o.DefaultTTL
+}
+
+// cacheKey различает мерчантов, так как им доступны разные заказы. Логин берётся
+// из Config.Secrets, если он там задан, как и ключ токена в TokenStore.
+func (s *Service) cacheKey(ctx context.Context, method, path string, query map[string]string) (string, error) {
+	credentials, err := s.credentials(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	values := url.Values{}
+	for key, value := range query {
+		values.Set(key, value)
+	}
+	return credentials.Login + " " + method + " " + path + "?" + values.Encode(), nil
+}
+
+// cachedResponse возвращает тело из кэша, если операция кэшируется и обход не запрошен.
+func (s *Service) cachedResponse(ctx context.Context, inputs *SendParams) ([]byte, bool) {
+	if s.cache == nil || inputs.HttpMethod != http.MethodGet || inputs.NoCache || s.cache.ttl(inputs.Operation) <= 0 {
+		return nil, false
+	}
+
+	key, err := s.cacheKey(ctx, inputs.HttpMethod, inputs.Path, inputs.QueryParams)
+	if err != nil {
+		s.logger.Warnf("response cache: %v", err)
+		return nil, false
+	}
+	body, ok, err := s.cache.Store.Get(ctx, key)
+	if err != nil {
+		s.logger.Warnf("response cache: %v", err)
+		return nil, false
+	}
+	return body, ok
+}
+
+var orderPathPattern = regexp.MustCompile(`^/v1/order/([^/]+)/`)
+
+// updateCache сохраняет успешный GET-ответ или сбрасывает статус заказа после изменяющего запроса.
+func (s *Service) updateCache(ctx context.Context, inputs *SendParams, body []byte) {
+	if s.cache == nil {
+		return
+	}
+
+	var err error
+	if inputs.HttpMethod == http.MethodGet {
+		if ttl := s.cache.ttl(inputs.Operation); ttl > 0 {
+			var key string
+			if key, err = s.cacheKey(ctx, inputs.HttpMethod, inputs.Path, inputs.QueryParams); err == nil {
+				err = s.cache.Store.Set(ctx, key, body, ttl)
+			}
+		}
+	} else if match := orderPathPattern.FindStringSubmatch(inputs.Path); match != nil {
+		var key string
+		if key, err = s.cacheKey(ctx, http.MethodGet, fmt.Sprintf(getPayment, match[1]), nil); err == nil {
+			err = s.cache.Store.Delete(ctx, key)
+		}
+	}
+	if err != nil {
+		s.logger.Warnf("response cache: %v", err)
+	}
+}
+
+// MemoryResponseCache — ResponseCache в памяти процесса.
+type MemoryResponseCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	body      []byte
+	expiresAt time.Time
+}
+
+func NewMemoryResponseCache() *MemoryResponseCache {
+	return &MemoryResponseCache{
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+func (m *MemoryResponseCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		delete(m.entries, key)
+		return nil, false, nil
+	}
+	return entry.body, true, nil
+}
+
+func (m *MemoryResponseCache) Set(_ context.Context, key string, body []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range m.entries {
+		if now.After(entry.expiresAt) {
+			delete(m.entries, k)
+		}
+	}
+	m.entries[key] = cacheEntry{body: body, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+func (m *MemoryResponseCache) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
@@ -0,0 +1,45 @@
+package softlinePayment_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	softline "github.com/dwnGnL/softlinePayment"
+	"github.com/dwnGnL/softlinePayment/softlinetest"
+)
+
+// secretsConfig — конфиг мерчанта, логин которого задан только в Secrets.
+func secretsConfig(server *softlinetest.Server, login string) *softline.Config {
+	config := server.Config()
+	config.Login, config.Pass = "", ""
+	config.Secrets = softline.SecretProviderFunc(func(context.Context) (softline.Credentials, error) {
+		return softline.Credentials{Login: login, Pass: "test"}, nil
+	})
+	return config
+}
+
+func TestCacheKeyedBySecretsLogin(t *testing.T) {
+	server := softlinetest.NewServer()
+	t.Cleanup(server.Close)
+
+	cache := softline.CacheOptions{Store: softline.NewMemoryResponseCache(), DefaultTTL: time.Minute}
+	first, err := softline.New(secretsConfig(server, "merchant-a"), softline.WithResponseCache(cache))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	second, err := softline.New(secretsConfig(server, "merchant-b"), softline.WithResponseCache(cache))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	for _, s := range []*softline.Service{first, second, first} {
+		if _, _, err := s.PostCheck(context.Background(), "42", ""); err != nil {
+			t.Fatalf("PostCheck: %v", err)
+		}
+	}
+	// второй мерчант не должен получить заказ первого из общего кэша
+	if calls := server.Calls(softlinetest.RouteOrder); calls != 2 {
+		t.Fatalf("order calls = %d, want 2", calls)
+	}
+}
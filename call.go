@@ -0,0 +1,68 @@
+package softlinePayment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// call — общий конвейер вызова эндпоинта: кодирует request (nil — без тела),
+// подставляет токен, применяет опции вызова, отправляет запрос и разбирает ответ в TResp.
+// Новый эндпоинт добавляется одним вызовом call с описанием SendParams.
+func call[TReq any, TResp any](ctx context.Context, s *Service, inputs *SendParams, request *TReq, token string, opts []RequestOption) (respBody []byte, response *TResp, err error) {
+	response = new(TResp)
+
+	if inputs.AuthNeed {
+		if inputs.Token, err = s.resolveToken(ctx, token); err != nil {
+			return
+		}
+	}
+
+	if request != nil {
+		body := new(bytes.Buffer)
+		if err = json.NewEncoder(body).Encode(request); err != nil {
+			err = fmt.Errorf("can't encode request: %s", err)
+			return
+		}
+		inputs.Body = body
+	}
+
+	inputs.apply(opts)
+	if inputs.Idempotent && inputs.HttpMethod == http.MethodPost && inputs.IdempotencyKey == "" {
+		inputs.IdempotencyKey = newUUID()
+	}
+
+	started := time.Now()
+	defer func() {
+		var auditRequest any
+		if request != nil {
+			auditRequest = request
+		}
+		s.auditCall(ctx, inputs, auditRequest, response, started, err)
+	}()
+
+	respBody, err = s.sendRequest(ctx, inputs)
+
+	if receiver, ok := any(response).(metaReceiver); ok && inputs.HttpCode != 0 {
+		receiver.setResponseMeta(inputs.Meta)
+	}
+
+	// например, DELETE с ответом 204
+	if len(bytes.TrimSpace(respBody)) == 0 {
+		return
+	}
+
+	if err != nil {
+		// ошибки валидации SOM кладёт в тело, оставляем их доступными в ответе
+		_ = json.Unmarshal(respBody, response)
+		return
+	}
+
+	if err = s.decodeResponse(inputs.Operation, respBody, response); err != nil {
+		err = &DecodeError{Operation: inputs.Operation, HTTPStatus: inputs.HttpCode, Body: respBody, Err: err}
+	}
+	return
+}
@@ -0,0 +1,78 @@
+package softlinePayment
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	customerCards = "/v1/customer/%s/cards"
+	customerCard  = "/v1/customer/%s/cards/%s"
+	payWithCard   = "/v1/payment/token"
+)
+
+// SaveCard сохраняет карту, которой оплачен заказ, в хранилище карт покупателя.
+func (s *Service) SaveCard(ctx context.Context, request SaveCardReq, token string, opts ...RequestOption) (respBody []byte, response *CardToken, err error) {
+	path, err := endpointPath(customerCards, request.CustomerID)
+	if err != nil {
+		return nil, new(CardToken), err
+	}
+
+	return call[SaveCardReq, CardToken](ctx, s, &SendParams{
+		Operation:  "save_card",
+		Path:       path,
+		HttpMethod: http.MethodPost,
+		AuthNeed:   true,
+	}, &request, token, opts)
+}
+
+func (s *Service) ListCards(ctx context.Context, customerID string, token string, opts ...RequestOption) (respBody []byte, response *CardTokenList, err error) {
+	path, err := endpointPath(customerCards, customerID)
+	if err != nil {
+		return nil, new(CardTokenList), err
+	}
+
+	return call[struct{}, CardTokenList](ctx, s, &SendParams{
+		Operation:  "list_cards",
+		Path:       path,
+		HttpMethod: http.MethodGet,
+		AuthNeed:   true,
+	}, nil, token, opts)
+}
+
+func (s *Service) DeleteCard(ctx context.Context, customerID string, cardToken string, token string, opts ...RequestOption) (respBody []byte, err error) {
+	path, err := endpointPath(customerCard, customerID, cardToken)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, _, err = call[struct{}, struct{}](ctx, s, &SendParams{
+		Operation:  "delete_card",
+		Path:       path,
+		HttpMethod: http.MethodDelete,
+		AuthNeed:   true,
+		Idempotent: true,
+	}, nil, token, opts)
+
+	return
+}
+
+// PayWithCard списывает оплату сохранённой картой по инициативе покупателя, без рекуррентного флага.
+func (s *Service) PayWithCard(ctx context.Context, data PayWithCardReq, token string, opts ...RequestOption) (respBody []byte, response *CreatePaymentResp, err error) {
+	if err = validatePaymentAmount(data.Amount, data.Currency); err != nil {
+		return nil, new(CreatePaymentResp), err
+	}
+
+	inputs := &SendParams{
+		Operation:      "pay_with_card",
+		Path:           payWithCard,
+		HttpMethod:     http.MethodPost,
+		AuthNeed:       true,
+		Idempotent:     true,
+		IdempotencyKey: data.IdempotencyKey,
+	}
+	respBody, response, err = call[PayWithCardReq, CreatePaymentResp](ctx, s, inputs, &data, token, opts)
+	response.IdempotencyKey = inputs.IdempotencyKey
+
+	return
+}
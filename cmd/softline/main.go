@@ -0,0 +1,215 @@
+// Команда softline — утилита для ручных операций с SOM: авторизация, создание платежа,
+// проверка статуса заказа, возврат и проверка подписи колбэка.
+//
+// Конфигурация читается из JSON- или YAML-файла (-config) и переменных окружения
+// SOFTLINE_URI, SOFTLINE_ENV, SOFTLINE_LOGIN, SOFTLINE_PASS (или SOFTLINE_PASS_FILE).
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	softline "github.com/dwnGnL/softlinePayment"
+)
+
+const usage = `usage: softline [-config file.json|file.yaml] [-timeout 30s] <command> [flags]
+
+commands:
+  auth                          get a JWT
+  ping                          check connectivity and credentials
+  create-payment [flags]        create a payment
+  status <orderID>              show order status
+  refund <orderID> [flags]      full refund of an order
+  verify-signature [flags]      verify a callback signature
+`
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "softline:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	global := flag.NewFlagSet("softline", flag.ContinueOnError)
+	global.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	configPath := global.String("config", "", "path to JSON or YAML config")
+	timeout := global.Duration("timeout", 30*time.Second, "overall command timeout")
+	if err := global.Parse(args); err != nil {
+		return err
+	}
+
+	if global.NArg() == 0 {
+		global.Usage()
+		return errors.New("command is required")
+	}
+	command, rest := global.Arg(0), global.Args()[1:]
+
+	// проверка подписи не ходит в SOM и не требует конфига
+	if command == "verify-signature" {
+		return verifySignature(rest)
+	}
+
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	service, err := softline.New(config)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	switch command {
+	case "auth":
+		resp, err := service.Auth(ctx)
+		if err != nil {
+			return err
+		}
+		return printJSON(resp)
+	case "ping":
+		latency, err := service.Ping(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("ok in %s\n", latency.Round(time.Millisecond))
+		return nil
+	case "create-payment":
+		return createPayment(ctx, service, rest)
+	case "status":
+		if len(rest) != 1 {
+			return errors.New("usage: softline status <orderID>")
+		}
+		_, resp, err := service.PostCheck(ctx, rest[0], "")
+		if err != nil {
+			return err
+		}
+		return printJSON(resp)
+	case "refund":
+		return refund(ctx, service, rest)
+	}
+
+	global.Usage()
+	return fmt.Errorf("unknown command %q", command)
+}
+
+func loadConfig(path string) (*softline.Config, error) {
+	if path == "" {
+		return softline.LoadConfigFromEnv()
+	}
+	return softline.LoadConfigFromFile(path)
+}
+
+func createPayment(ctx context.Context, service *softline.Service, args []string) error {
+	fs := flag.NewFlagSet("create-payment", flag.ContinueOnError)
+	amount := fs.String("amount", "", "amount, e.g. 100.00")
+	currency := fs.String("currency", "RUB", "ISO 4217 currency code")
+	paymentID := fs.String("payment-id", "", "merchant payment id")
+	description := fs.String("description", "", "payment description")
+	method := fs.String("method", "", "payment method")
+	returnURL := fs.String("return-url", "", "return success url")
+	email := fs.String("email", "", "customer email")
+	recurring := fs.Bool("recurring", false, "save card for recurring payments")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	value, err := softline.ParseAmount(*amount)
+	if err != nil {
+		return err
+	}
+
+	_, resp, err := service.CreatePayment(ctx, softline.CreatePaymentReq{
+		Currency:           *currency,
+		Amount:             value,
+		ReturnSuccessUrl:   *returnURL,
+		PaymentMethod:      *method,
+		RecurringIndicator: *recurring,
+		PaymentId:          *paymentID,
+		PaymentDescription: *description,
+		Customer: softline.Customer{
+			Email: *email,
+		},
+	}, "")
+	if err != nil {
+		return err
+	}
+	return printJSON(resp)
+}
+
+func refund(ctx context.Context, service *softline.Service, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: softline refund <orderID> [-email ...] [-description ...]")
+	}
+	orderID := args[0]
+
+	fs := flag.NewFlagSet("refund", flag.ContinueOnError)
+	email := fs.String("email", "", "customer email")
+	description := fs.String("description", "", "refund description")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	resp, err := service.Refund(ctx, softline.RefundReq{
+		OrderID:     orderID,
+		Email:       *email,
+		Description: *description,
+	}, "")
+	if err != nil {
+		return err
+	}
+	return printJSON(resp)
+}
+
+func verifySignature(args []string) error {
+	fs := flag.NewFlagSet("verify-signature", flag.ContinueOnError)
+	signature := fs.String("signature", "", "signature to verify")
+	secret := fs.String("secret", os.Getenv("SOFTLINE_SECRET_KEY"), "secret key (default $SOFTLINE_SECRET_KEY)")
+	version := fs.String("version", softline.SignatureV1, "signature scheme version")
+	event := fs.String("event", "", "event")
+	orderID := fs.Int64("order-id", 0, "order id")
+	createDate := fs.String("create-date", "", "create_date exactly as in the callback")
+	method := fs.String("method", "", "payment method")
+	currency := fs.String("currency", "", "currency")
+	email := fs.String("email", "", "customer email")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	scheme, err := softline.SignatureSchemeByVersion(*version)
+	if err != nil {
+		return err
+	}
+
+	expected := scheme.Sign(softline.Signature{
+		SecretKey:     *secret,
+		Event:         *event,
+		OrderID:       strconv.FormatInt(*orderID, 10),
+		CreateDate:    *createDate,
+		PaymentMethod: *method,
+		Currency:      *currency,
+		CustomerEmail: *email,
+	})
+
+	if expected != *signature {
+		fmt.Println("INVALID")
+		fmt.Println("expected:", expected)
+		return errors.New("signature mismatch")
+	}
+	fmt.Println("OK")
+	return nil
+}
+
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -1,9 +1,198 @@
 package softlinePayment
 
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultIdleConnTimeoutSec     = 90
+	defaultRequestTimeoutSec      = 30
+	defaultTokenRefreshAheadSec   = 30
+	defaultMaxIdleConnsPerHost    = 16
+	defaultDialTimeoutSec         = 30
+	defaultKeepAliveSec           = 30
+	defaultTLSHandshakeTimeoutSec = 10
+)
+
 type Config struct {
-	IdleConnTimeoutSec int
-	RequestTimeoutSec  int
-	Login              string
-	Pass               string
-	URI                string
+	IdleConnTimeoutSec     int
+	RequestTimeoutSec      int
+	Login                  string
+	Pass                   string
+	URI                    string
+	Environment            Environment
+	Retry                  RetryPolicy
+	RateLimitRPS           float64
+	RateLimitBurst         int
+	SignatureVersion       string
+	MaxResponseBytes       int64
+	Merchants              map[string]MerchantCredentials
+	FallbackURIs           []string
+	HedgeDelayMs           int
+	TokenRefreshAheadSec   int
+	TokenClockSkewSec      int
+	DryRun                 bool
+	DryRunOutcome          SimulatedOutcome
+	TLS                    *TLSConfig
+	HostHeader             string
+	OperationTimeoutSec    map[string]int
+	SigningKey             string
+	MaxIdleConnsPerHost    int
+	MaxConnsPerHost        int
+	DisableHTTP2           bool
+	DialTimeoutSec         int
+	TLSHandshakeTimeoutSec int
+	KeepAliveSec           int
+	MaxConnAgeSec          int
+	Secrets                SecretProvider `json:"-"`
+}
+
+// Validate проверяет конфиг и возвращает все найденные ошибки разом.
+func (c *Config) Validate() error {
+	if c == nil {
+		return errors.New("softline: config is nil")
+	}
+
+	var errs []error
+
+	switch c.Environment {
+	case "", EnvSandbox, EnvProduction:
+	default:
+		errs = append(errs, fmt.Errorf("unknown environment %q", c.Environment))
+	}
+
+	uri := c.URI
+	if uri == "" {
+		uri = c.Environment.BaseURI()
+	}
+	if uri == "" {
+		errs = append(errs, errors.New("URI or Environment is required"))
+	} else if err := validateURI("URI", uri); err != nil {
+		errs = append(errs, err)
+	}
+	for i, fallback := range c.FallbackURIs {
+		if err := validateURI(fmt.Sprintf("FallbackURIs[%d]", i), fallback); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.TokenRefreshAheadSec < 0 || c.TokenClockSkewSec < 0 {
+		errs = append(errs, errors.New("TokenRefreshAheadSec and TokenClockSkewSec must not be negative"))
+	}
+	if c.TLS != nil {
+		if err := c.TLS.validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	switch c.DryRunOutcome {
+	case "", SimulateSuccess, Simulate3DS, SimulateDecline:
+	default:
+		errs = append(errs, fmt.Errorf("unknown DryRunOutcome %q", c.DryRunOutcome))
+	}
+	if c.HedgeDelayMs < 0 {
+		errs = append(errs, errors.New("HedgeDelayMs must not be negative"))
+	}
+
+	// с Secrets учётные данные могут приходить только из провайдера
+	if c.Login == "" && c.Secrets == nil {
+		errs = append(errs, errors.New("Login is required"))
+	}
+	if c.Pass == "" && c.Secrets == nil {
+		errs = append(errs, errors.New("Pass is required"))
+	}
+	for id, merchant := range c.Merchants {
+		if merchant.Login == "" || merchant.Pass == "" {
+			errs = append(errs, fmt.Errorf("merchant %q: Login and Pass are required", id))
+		}
+	}
+	if c.IdleConnTimeoutSec < 0 {
+		errs = append(errs, errors.New("IdleConnTimeoutSec must not be negative"))
+	}
+	if c.RequestTimeoutSec < 0 {
+		errs = append(errs, errors.New("RequestTimeoutSec must not be negative"))
+	}
+	if c.MaxIdleConnsPerHost < 0 || c.MaxConnsPerHost < 0 {
+		errs = append(errs, errors.New("MaxIdleConnsPerHost and MaxConnsPerHost must not be negative"))
+	}
+	if c.DialTimeoutSec < 0 || c.TLSHandshakeTimeoutSec < 0 || c.KeepAliveSec < 0 {
+		errs = append(errs, errors.New("DialTimeoutSec, TLSHandshakeTimeoutSec and KeepAliveSec must not be negative"))
+	}
+	if c.MaxConnAgeSec < 0 {
+		errs = append(errs, errors.New("MaxConnAgeSec must not be negative"))
+	}
+	for operation, seconds := range c.OperationTimeoutSec {
+		if seconds < 0 {
+			errs = append(errs, fmt.Errorf("OperationTimeoutSec[%q] must not be negative", operation))
+		}
+	}
+	if c.Retry.MaxAttempts < 0 {
+		errs = append(errs, errors.New("Retry.MaxAttempts must not be negative"))
+	}
+	if c.Retry.Jitter < 0 || c.Retry.Jitter > 1 {
+		errs = append(errs, errors.New("Retry.Jitter must be between 0 and 1"))
+	}
+	if _, err := SignatureSchemeByVersion(c.SignatureVersion); err != nil {
+		errs = append(errs, err)
+	}
+	if c.MaxResponseBytes < 0 {
+		errs = append(errs, errors.New("MaxResponseBytes must not be negative"))
+	}
+	if c.RateLimitRPS < 0 {
+		errs = append(errs, errors.New("RateLimitRPS must not be negative"))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("softline: invalid config: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func validateURI(field, uri string) error {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return fmt.Errorf("can't parse %s: %w", field, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s must be an absolute http(s) URL, got %q", field, uri)
+	}
+	return nil
+}
+
+// withDefaults возвращает копию конфига с заполненными значениями по умолчанию.
+func (c Config) withDefaults() Config {
+	if c.IdleConnTimeoutSec == 0 {
+		c.IdleConnTimeoutSec = defaultIdleConnTimeoutSec
+	}
+	if c.RequestTimeoutSec == 0 {
+		c.RequestTimeoutSec = defaultRequestTimeoutSec
+	}
+	if c.TokenRefreshAheadSec == 0 {
+		c.TokenRefreshAheadSec = defaultTokenRefreshAheadSec
+	}
+	if c.MaxResponseBytes == 0 {
+		c.MaxResponseBytes = defaultMaxResponseBytes
+	}
+	if c.MaxIdleConnsPerHost == 0 {
+		c.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	}
+	if c.DialTimeoutSec == 0 {
+		c.DialTimeoutSec = defaultDialTimeoutSec
+	}
+	if c.KeepAliveSec == 0 {
+		c.KeepAliveSec = defaultKeepAliveSec
+	}
+	if c.TLSHandshakeTimeoutSec == 0 {
+		c.TLSHandshakeTimeoutSec = defaultTLSHandshakeTimeoutSec
+	}
+	return c
+}
+
+func (c *Config) tokenRefreshAhead() time.Duration {
+	return time.Duration(c.TokenRefreshAheadSec) * time.Second
+}
+
+func (c *Config) tokenClockSkew() time.Duration {
+	return time.Duration(c.TokenClockSkewSec) * time.Second
 }
@@ -0,0 +1,145 @@
+package softlinePayment
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Переменные окружения, которые читают LoadConfigFromEnv и LoadConfigFromFile.
+// Для секретов вместо значения можно указать путь к файлу в переменной с суффиксом _FILE,
+// например SOFTLINE_PASS_FILE=/run/secrets/softline_pass.
+const (
+	EnvURI               = "SOFTLINE_URI"
+	EnvEnvironment       = "SOFTLINE_ENV"
+	EnvLogin             = "SOFTLINE_LOGIN"
+	EnvPass              = "SOFTLINE_PASS"
+	EnvSigningKey        = "SOFTLINE_SIGNING_KEY"
+	EnvFallbackURIs      = "SOFTLINE_FALLBACK_URIS" // через запятую
+	EnvHostHeader        = "SOFTLINE_HOST_HEADER"
+	EnvRequestTimeoutSec = "SOFTLINE_REQUEST_TIMEOUT_SEC"
+	EnvDryRun            = "SOFTLINE_DRY_RUN"
+
+	secretFileSuffix = "_FILE"
+)
+
+// LoadConfigFromEnv собирает конфиг из переменных окружения SOFTLINE_*,
+// заполняет значения по умолчанию и проверяет результат.
+func LoadConfigFromEnv() (*Config, error) {
+	config := new(Config)
+	if err := config.applyEnv(); err != nil {
+		return nil, err
+	}
+	return config.loaded()
+}
+
+// LoadConfigFromFile читает конфиг из JSON или YAML (по расширению .yaml/.yml).
+// Ключи совпадают с именами полей Config; переменные окружения перекрывают файл.
+func LoadConfigFromFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't read config: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		// YAML приводится к JSON, чтобы ключи сопоставлялись с полями так же, как в JSON
+		var document interface{}
+		if err = yaml.Unmarshal(data, &document); err != nil {
+			return nil, fmt.Errorf("can't parse config: %w", err)
+		}
+		if data, err = json.Marshal(document); err != nil {
+			return nil, fmt.Errorf("can't parse config: %w", err)
+		}
+	}
+
+	config := new(Config)
+	if err = json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("can't parse config: %w", err)
+	}
+	if err = config.applyEnv(); err != nil {
+		return nil, err
+	}
+	return config.loaded()
+}
+
+func (c *Config) loaded() (*Config, error) {
+	config := c.withDefaults()
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (c *Config) applyEnv() error {
+	if v := os.Getenv(EnvURI); v != "" {
+		c.URI = v
+	}
+	if v := os.Getenv(EnvEnvironment); v != "" {
+		c.Environment = Environment(v)
+	}
+	if v := os.Getenv(EnvLogin); v != "" {
+		c.Login = v
+	}
+	if v := os.Getenv(EnvHostHeader); v != "" {
+		c.HostHeader = v
+	}
+	if v := os.Getenv(EnvFallbackURIs); v != "" {
+		c.FallbackURIs = nil
+		for _, uri := range strings.Split(v, ",") {
+			if uri = strings.TrimSpace(uri); uri != "" {
+				c.FallbackURIs = append(c.FallbackURIs, uri)
+			}
+		}
+	}
+	if v := os.Getenv(EnvRequestTimeoutSec); v != "" {
+		seconds, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("can't parse %s: %w", EnvRequestTimeoutSec, err)
+		}
+		c.RequestTimeoutSec = seconds
+	}
+	if v := os.Getenv(EnvDryRun); v != "" {
+		dryRun, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("can't parse %s: %w", EnvDryRun, err)
+		}
+		c.DryRun = dryRun
+	}
+
+	for name, target := range map[string]*string{
+		EnvPass:       &c.Pass,
+		EnvSigningKey: &c.SigningKey,
+	} {
+		secret, ok, err := secretFromEnv(name)
+		if err != nil {
+			return err
+		}
+		if ok {
+			*target = secret
+		}
+	}
+	return nil
+}
+
+// secretFromEnv читает секрет из переменной name или из файла, путь к которому в name_FILE.
+func secretFromEnv(name string) (string, bool, error) {
+	if v := os.Getenv(name); v != "" {
+		return v, true, nil
+	}
+
+	path := os.Getenv(name + secretFileSuffix)
+	if path == "" {
+		return "", false, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("can't read %s: %w", name+secretFileSuffix, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), true, nil
+}
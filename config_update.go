@@ -0,0 +1,123 @@
+package softlinePayment
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// liveConfig — настройки, которые UpdateConfig заменяет целиком одной атомарной операцией.
+type liveConfig struct {
+	config       *Config
+	baseURL      string
+	fallbackURLs []string
+	hedgeDelay   time.Duration
+	tokens       *TokenManager
+	merchants    map[string]*Service
+}
+
+func (s *Service) newLiveConfig(config *Config) *liveConfig {
+	live := &liveConfig{
+		config:       config,
+		baseURL:      s.baseURL,
+		fallbackURLs: config.FallbackURIs,
+		hedgeDelay:   time.Duration(config.HedgeDelayMs) * time.Millisecond,
+	}
+	if live.baseURL == "" {
+		live.baseURL = config.URI
+	}
+	if live.baseURL == "" {
+		live.baseURL = config.Environment.BaseURI()
+	}
+	live.tokens = newTokenManager(s.refreshToken, s.tokenStore, tokenStoreKey(config), config.tokenRefreshAhead(), config.tokenClockSkew())
+	live.merchants = s.newMerchants(config)
+	return live
+}
+
+func (s *Service) current() *liveConfig {
+	return s.live.Load()
+}
+
+func (s *Service) cfg() *Config {
+	return s.live.Load().config
+}
+
+// Config возвращает копию действующего конфига с заполненными значениями по умолчанию.
+func (s *Service) Config() Config {
+	return *s.cfg()
+}
+
+// UpdateConfig атомарно заменяет учётные данные, адреса SOM и прочие настройки запросов
+// на работающем Service, например при ротации пароля. Кэш токена сбрасывается, следующий
+// запрос авторизуется с новыми данными; уже выполняющиеся запросы завершаются со старыми.
+// Настройки HTTP-транспорта, повторов, лимитера и режима симуляции задаются только в New:
+// если они отличаются от действующих, возвращается ошибка и конфиг не меняется.
+// Если адрес задан через WithBaseURL, URI и Environment тоже менять нельзя.
+func (s *Service) UpdateConfig(ctx context.Context, config *Config) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	cfg := config.withDefaults()
+
+	s.updateMu.Lock()
+	defer s.updateMu.Unlock()
+
+	old := s.current()
+	fields := fixedFieldsChanged(old.config, &cfg)
+	if s.baseURL != "" {
+		// WithBaseURL переопределяет Config.URI, новый адрес молча не применился бы
+		if old.config.URI != cfg.URI {
+			fields = append(fields, "URI")
+		}
+		if old.config.Environment != cfg.Environment {
+			fields = append(fields, "Environment")
+		}
+	}
+	if len(fields) > 0 {
+		return fmt.Errorf("softline: %s can't be changed by UpdateConfig, create a new Service", strings.Join(fields, ", "))
+	}
+
+	// токен старых учётных данных больше не нужен ни здесь, ни в общем TokenStore. Сбрасываем
+	// до замены конфига: при том же ключе в TokenStore удаление после неё стёрло бы токен нового.
+	if err := old.tokens.Invalidate(ctx); err != nil {
+		s.logger.Warnf("can't invalidate token: %v", err)
+	}
+	for _, merchant := range old.merchants {
+		if err := merchant.current().tokens.Invalidate(ctx); err != nil {
+			s.logger.Warnf("can't invalidate merchant token: %v", err)
+		}
+	}
+
+	s.live.Store(s.newLiveConfig(&cfg))
+	return nil
+}
+
+// fixedFieldsChanged возвращает поля, которые применяются только при создании Service.
+func fixedFieldsChanged(old, new *Config) []string {
+	var fields []string
+	check := func(name string, changed bool) {
+		if changed {
+			fields = append(fields, name)
+		}
+	}
+
+	check("IdleConnTimeoutSec", old.IdleConnTimeoutSec != new.IdleConnTimeoutSec)
+	check("RequestTimeoutSec", old.RequestTimeoutSec != new.RequestTimeoutSec)
+	check("Retry", !reflect.DeepEqual(old.Retry, new.Retry))
+	check("RateLimitRPS", old.RateLimitRPS != new.RateLimitRPS)
+	check("RateLimitBurst", old.RateLimitBurst != new.RateLimitBurst)
+	check("SignatureVersion", old.SignatureVersion != new.SignatureVersion)
+	check("DryRun", old.DryRun != new.DryRun)
+	check("DryRunOutcome", old.DryRunOutcome != new.DryRunOutcome)
+	check("TLS", !reflect.DeepEqual(old.TLS, new.TLS))
+	check("MaxIdleConnsPerHost", old.MaxIdleConnsPerHost != new.MaxIdleConnsPerHost)
+	check("MaxConnsPerHost", old.MaxConnsPerHost != new.MaxConnsPerHost)
+	check("DisableHTTP2", old.DisableHTTP2 != new.DisableHTTP2)
+	check("DialTimeoutSec", old.DialTimeoutSec != new.DialTimeoutSec)
+	check("TLSHandshakeTimeoutSec", old.TLSHandshakeTimeoutSec != new.TLSHandshakeTimeoutSec)
+	check("KeepAliveSec", old.KeepAliveSec != new.KeepAliveSec)
+	check("MaxConnAgeSec", old.MaxConnAgeSec != new.MaxConnAgeSec)
+	return fields
+}
@@ -0,0 +1,47 @@
+package softlinePayment
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// connRecycler закрывает простаивающие соединения раз в MaxConnAgeSec и после
+// отказа хоста. Новые соединения заново резолвят DNS, поэтому после переключения
+// Softline на другие IP клиент уходит с мёртвого адреса без перезапуска процесса.
+// Соединение, занятое запросом, закрывается при следующей проверке, когда оно свободно.
+type connRecycler struct {
+	maxAge    time.Duration
+	lastFlush atomic.Int64
+}
+
+func newConnRecycler(maxAge time.Duration) *connRecycler {
+	r := &connRecycler{maxAge: maxAge}
+	r.lastFlush.Store(time.Now().UnixNano())
+	return r
+}
+
+// idleCloser реализуют *http.Client и *http.Transport.
+type idleCloser interface {
+	CloseIdleConnections()
+}
+
+// maybeRecycleConns вызывается перед каждым запросом.
+func (s *Service) maybeRecycleConns() {
+	r := s.connRecycler
+	if r == nil || r.maxAge <= 0 {
+		return
+	}
+
+	now := time.Now().UnixNano()
+	last := r.lastFlush.Load()
+	if time.Duration(now-last) < r.maxAge || !r.lastFlush.CompareAndSwap(last, now) {
+		return
+	}
+	s.closeIdleConns()
+}
+
+func (s *Service) closeIdleConns() {
+	if closer, ok := s.client.(idleCloser); ok {
+		closer.CloseIdleConnections()
+	}
+}
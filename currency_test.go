@@ -0,0 +1,43 @@
+package softlinePayment
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidatePaymentAmountKeepsMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"make payment without currency", (&MakePaymentReq{ParentOrderId: 1}).Validate(), "amount must be positive, got 0"},
+		{"make payment with currency", (&MakePaymentReq{ParentOrderId: 1, Amount: NewAmount(-100, 2), Currency: "RUB"}).Validate(), "amount must be positive, got -1.00"},
+		{"partial refund", (&PartialRefundReq{OrderID: "42", Amount: NewAmount(-5, 0)}).Validate(), "amount must be positive, got -5"},
+		{"too precise", (&MakePaymentReq{ParentOrderId: 1, Amount: NewAmount(1005, 3), Currency: "RUB"}).Validate(), "RUB allows 2 decimal places, got 1.005"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, ErrValidation) || !errors.Is(tt.err, ErrInvalidAmount) {
+				t.Fatalf("err = %v, want %v and %v", tt.err, ErrValidation, ErrInvalidAmount)
+			}
+			if !strings.Contains(tt.err.Error(), tt.want) {
+				t.Fatalf("err = %q, want it to contain %q", tt.err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateAmount(t *testing.T) {
+	if err := ValidateAmount(NewAmount(10050, 2), "rub"); err != nil {
+		t.Fatalf("ValidateAmount: %v", err)
+	}
+	if err := ValidateAmount(NewAmount(100, 0), "XXX"); !errors.Is(err, ErrInvalidCurrency) {
+		t.Fatalf("ValidateAmount = %v, want %v", err, ErrInvalidCurrency)
+	}
+	if err := ValidateAmount(NewAmount(1, 1), "JPY"); !errors.Is(err, ErrInvalidAmount) {
+		t.Fatalf("ValidateAmount = %v, want %v", err, ErrInvalidAmount)
+	}
+}
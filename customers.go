@@ -0,0 +1,70 @@
+package softlinePayment
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	customers = "/v1/customer"
+	customer  = "/v1/customer/%s"
+)
+
+func (s *Service) CreateCustomer(ctx context.Context, data CreateCustomerReq, token string, opts ...RequestOption) (respBody []byte, response *CustomerProfile, err error) {
+	inputs := &SendParams{
+		Operation:      "create_customer",
+		Path:           customers,
+		HttpMethod:     http.MethodPost,
+		AuthNeed:       true,
+		Idempotent:     true,
+		IdempotencyKey: data.IdempotencyKey,
+	}
+
+	return call[CreateCustomerReq, CustomerProfile](ctx, s, inputs, &data, token, opts)
+}
+
+func (s *Service) GetCustomer(ctx context.Context, customerID string, token string, opts ...RequestOption) (respBody []byte, response *CustomerProfile, err error) {
+	path, err := endpointPath(customer, customerID)
+	if err != nil {
+		return nil, new(CustomerProfile), err
+	}
+
+	return call[struct{}, CustomerProfile](ctx, s, &SendParams{
+		Operation:  "get_customer",
+		Path:       path,
+		HttpMethod: http.MethodGet,
+		AuthNeed:   true,
+	}, nil, token, opts)
+}
+
+func (s *Service) UpdateCustomer(ctx context.Context, data UpdateCustomerReq, token string, opts ...RequestOption) (respBody []byte, response *CustomerProfile, err error) {
+	path, err := endpointPath(customer, data.CustomerID)
+	if err != nil {
+		return nil, new(CustomerProfile), err
+	}
+
+	return call[UpdateCustomerReq, CustomerProfile](ctx, s, &SendParams{
+		Operation:  "update_customer",
+		Path:       path,
+		HttpMethod: http.MethodPatch,
+		AuthNeed:   true,
+	}, &data, token, opts)
+}
+
+// DeleteCustomer удаляет покупателя вместе с сохранёнными картами.
+func (s *Service) DeleteCustomer(ctx context.Context, customerID string, token string, opts ...RequestOption) (respBody []byte, err error) {
+	path, err := endpointPath(customer, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, _, err = call[struct{}, struct{}](ctx, s, &SendParams{
+		Operation:  "delete_customer",
+		Path:       path,
+		HttpMethod: http.MethodDelete,
+		AuthNeed:   true,
+		Idempotent: true,
+	}, nil, token, opts)
+
+	return
+}
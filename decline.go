@@ -0,0 +1,73 @@
+package softlinePayment
+
+import "strings"
+
+// DeclineReason — причина отказа в платеже, сведённая из кодов SOM и банка-эмитента.
+type DeclineReason string
+
+const (
+	DeclineNone              DeclineReason = ""
+	DeclineInsufficientFunds DeclineReason = "insufficient_funds"
+	DeclineExpiredCard       DeclineReason = "expired_card"
+	DeclineDoNotHonor        DeclineReason = "do_not_honor"
+	DeclineFraudSuspected    DeclineReason = "fraud_suspected"
+	DeclineThreeDSFailed     DeclineReason = "3ds_failed"
+	DeclineUnknown           DeclineReason = "unknown"
+)
+
+// коды отказа SOM: числовые коды эмитента (ISO 8583) и текстовые коды самого SOM
+var declineCodes = map[string]DeclineReason{
+	"51":                    DeclineInsufficientFunds,
+	"61":                    DeclineInsufficientFunds,
+	"insufficient_funds":    DeclineInsufficientFunds,
+	"33":                    DeclineExpiredCard,
+	"54":                    DeclineExpiredCard,
+	"expired_card":          DeclineExpiredCard,
+	"05":                    DeclineDoNotHonor,
+	"do_not_honor":          DeclineDoNotHonor,
+	"34":                    DeclineFraudSuspected,
+	"41":                    DeclineFraudSuspected,
+	"43":                    DeclineFraudSuspected,
+	"59":                    DeclineFraudSuspected,
+	"fraud":                 DeclineFraudSuspected,
+	"fraud_suspected":       DeclineFraudSuspected,
+	"3ds_failed":            DeclineThreeDSFailed,
+	"3ds_not_passed":        DeclineThreeDSFailed,
+	"authentication_failed": DeclineThreeDSFailed,
+}
+
+// ParseDeclineReason сопоставляет код отказа SOM с DeclineReason.
+// Пустой код — DeclineNone, незнакомый — DeclineUnknown.
+func ParseDeclineReason(code string) DeclineReason {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		return DeclineNone
+	}
+	if reason, ok := declineCodes[code]; ok {
+		return reason
+	}
+	return DeclineUnknown
+}
+
+// Retryable — повторное списание с той же карты позже может пройти.
+func (r DeclineReason) Retryable() bool {
+	switch r {
+	case DeclineInsufficientFunds, DeclineDoNotHonor, DeclineThreeDSFailed:
+		return true
+	}
+	return false
+}
+
+func (r DeclineReason) String() string {
+	return string(r)
+}
+
+// DeclineReason возвращает причину отказа по payment.payment_error_code.
+func (p *PaymentResp) DeclineReason() DeclineReason {
+	return ParseDeclineReason(p.Payment.ErrorCode)
+}
+
+// DeclineReason возвращает причину отказа по payment_error_code.
+func (r *CreatePaymentResp) DeclineReason() DeclineReason {
+	return ParseDeclineReason(r.ErrorCode)
+}
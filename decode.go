@@ -0,0 +1,51 @@
+package softlinePayment
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// DecodeMode определяет реакцию на поля ответа, которых нет в структурах SDK.
+type DecodeMode int
+
+const (
+	// DecodeLenient молча игнорирует неизвестные поля.
+	DecodeLenient DecodeMode = iota
+	// DecodeWarnUnknown пишет неизвестные поля в лог с уровнем Warn.
+	DecodeWarnUnknown
+	// DecodeStrict возвращает ошибку на неизвестном поле.
+	DecodeStrict
+)
+
+// WithDecodeMode задаёт режим разбора ответов SOM.
+func WithDecodeMode(mode DecodeMode) Option {
+	return func(s *Service) {
+		s.decodeMode = mode
+	}
+}
+
+func (s *Service) decodeResponse(operation string, body []byte, out interface{}) error {
+	if s.decodeMode == DecodeLenient {
+		return json.Unmarshal(body, out)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(out)
+	if err == nil || !isUnknownFieldError(err) {
+		return err
+	}
+	if s.decodeMode == DecodeStrict {
+		return err
+	}
+
+	s.logger.Warnf("%s: response contains fields unknown to the SDK: %v", operation, err)
+	return json.Unmarshal(body, out)
+}
+
+func isUnknownFieldError(err error) bool {
+	// encoding/json не экспортирует тип этой ошибки
+	return strings.HasPrefix(err.Error(), "json: unknown field ")
+}
@@ -0,0 +1,94 @@
+package softlinePayment
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+const (
+	disputes        = "/v1/dispute"
+	dispute         = "/v1/dispute/%s"
+	disputeEvidence = "/v1/dispute/%s/evidence"
+)
+
+func (s *Service) ListDisputes(ctx context.Context, request ListDisputesReq, token string, opts ...RequestOption) (respBody []byte, response *DisputeList, err error) {
+	query := queryParams{}.
+		Set("status", string(request.Status)).
+		SetTime("date_from", request.From).
+		SetTime("date_to", request.To).
+		Set("cursor", request.Cursor).
+		SetInt("limit", request.Limit)
+
+	return call[struct{}, DisputeList](ctx, s, &SendParams{
+		Operation:   "list_disputes",
+		Path:        disputes,
+		HttpMethod:  http.MethodGet,
+		AuthNeed:    true,
+		QueryParams: query,
+	}, nil, token, opts)
+}
+
+func (s *Service) GetDispute(ctx context.Context, disputeID string, token string, opts ...RequestOption) (respBody []byte, response *Dispute, err error) {
+	path, err := endpointPath(dispute, disputeID)
+	if err != nil {
+		return nil, new(Dispute), err
+	}
+
+	return call[struct{}, Dispute](ctx, s, &SendParams{
+		Operation:  "get_dispute",
+		Path:       path,
+		HttpMethod: http.MethodGet,
+		AuthNeed:   true,
+	}, nil, token, opts)
+}
+
+// UploadEvidence загружает документ-доказательство по спору в multipart/form-data.
+func (s *Service) UploadEvidence(ctx context.Context, request UploadEvidenceReq, token string, opts ...RequestOption) (respBody []byte, response *Dispute, err error) {
+	// файл не буферизуется целиком: multipart пишется в pipe параллельно с отправкой
+	body, pipe := io.Pipe()
+	defer body.Close()
+
+	writer := multipart.NewWriter(pipe)
+	contentType := writer.FormDataContentType()
+
+	go func() {
+		pipe.CloseWithError(writeEvidence(writer, request))
+	}()
+
+	path, err := endpointPath(disputeEvidence, request.DisputeID)
+	if err != nil {
+		return nil, new(Dispute), err
+	}
+
+	return call[struct{}, Dispute](ctx, s, &SendParams{
+		Operation:   "upload_evidence",
+		Path:        path,
+		HttpMethod:  http.MethodPost,
+		ContentType: contentType,
+		AuthNeed:    true,
+		Body:        body,
+		Stream:      true,
+	}, nil, token, opts)
+}
+
+func writeEvidence(writer *multipart.Writer, request UploadEvidenceReq) error {
+	if request.Description != "" {
+		if err := writer.WriteField("description", request.Description); err != nil {
+			return fmt.Errorf("can't encode request: %s", err)
+		}
+	}
+	part, err := writer.CreateFormFile("file", request.FileName)
+	if err != nil {
+		return fmt.Errorf("can't encode request: %s", err)
+	}
+	if _, err = io.Copy(part, request.Content); err != nil {
+		return fmt.Errorf("can't read evidence content: %s", err)
+	}
+	if err = writer.Close(); err != nil {
+		return fmt.Errorf("can't encode request: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package softlinePayment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Do вызывает эндпоинт SOM, для которого в SDK ещё нет метода, через общий конвейер:
+// токен, лимиты, повторы, разбор ошибок и метрики. path — путь от базового URL,
+// например "/v1/order/42/events?limit=10". body кодируется в JSON (nil — без тела, io.Reader — поток как есть),
+// ответ разбирается в out (nil — не разбирать). PUT и DELETE считаются идемпотентными,
+// POST повторяется только с WithIdempotencyKey.
+func (s *Service) Do(ctx context.Context, method, path string, body, out interface{}, opts ...RequestOption) (respBody []byte, err error) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("softline! Do: path must start with '/', got %q", path)
+	}
+
+	parsed, err := url.Parse(path)
+	if err != nil {
+		return nil, fmt.Errorf("softline! Do: can't parse path: %w", err)
+	}
+
+	inputs := &SendParams{
+		Operation:  "do",
+		Path:       parsed.EscapedPath(),
+		HttpMethod: method,
+		AuthNeed:   true,
+		Idempotent: method == http.MethodPut || method == http.MethodDelete,
+	}
+	if query := parsed.Query(); len(query) > 0 {
+		inputs.QueryParams = make(map[string]string, len(query))
+		for key := range query {
+			inputs.QueryParams[key] = query.Get(key)
+		}
+	}
+
+	// io.Reader отправляется потоком как есть, остальное кодируется в JSON
+	var request *interface{}
+	if reader, ok := body.(io.Reader); ok {
+		inputs.Body = reader
+		inputs.Stream = true
+	} else if body != nil {
+		request = &body
+	}
+
+	respBody, _, err = call[interface{}, json.RawMessage](ctx, s, inputs, request, "", opts)
+	if err != nil || out == nil || len(respBody) == 0 {
+		return
+	}
+
+	if err = s.decodeResponse(inputs.Operation, respBody, out); err != nil {
+		err = &DecodeError{Operation: inputs.Operation, HTTPStatus: inputs.HttpCode, Body: respBody, Err: err}
+	}
+	return
+}
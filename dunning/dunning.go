@@ -0,0 +1,224 @@
+// Package dunning повторяет отклонённые рекуррентные списания (MakePayment) по расписанию,
+// например через 1, 3 и 7 дней, и сообщает об окончательной неудаче.
+package dunning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	softline "github.com/dwnGnL/softlinePayment"
+)
+
+var ErrDeclined = errors.New("dunning: payment declined")
+
+type State string
+
+const (
+	StateRetrying  State = "retrying"
+	StateRecovered State = "recovered"
+	StateFailed    State = "failed"
+)
+
+const (
+	defaultInterval  = time.Minute
+	defaultLease     = 5 * time.Minute
+	defaultBatchSize = 50
+)
+
+// Policy — расписание повторов: i-й повтор выполняется через Delays[i] после предыдущего отклонения.
+// Число повторов равно len(Delays). Временные ошибки (таймаут, 5xx, сеть) попыткой не считаются.
+type Policy struct {
+	Delays []time.Duration
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		Delays: []time.Duration{24 * time.Hour, 72 * time.Hour, 7 * 24 * time.Hour},
+	}
+}
+
+// Charge — состояние списания в процессе дожима.
+type Charge struct {
+	ID            string
+	Request       softline.MakePaymentReq
+	State         State
+	Attempts      int // число отклонённых попыток
+	NextAttemptAt time.Time
+	LastStatus    softline.PaymentStatus
+	LastError     string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// Client — метод SOM, которым выполняются списания. Ему удовлетворяет *softline.Service.
+type Client interface {
+	MakePayment(ctx context.Context, data softline.MakePaymentReq, token string, opts ...softline.RequestOption) ([]byte, *softline.CreatePaymentResp, error)
+}
+
+// Engine выполняет списания и планирует повторы отклонённых.
+type Engine struct {
+	store  Store
+	client Client
+	policy Policy
+
+	Interval  time.Duration
+	Lease     time.Duration
+	BatchSize int
+
+	// списание прошло после одного или нескольких повторов
+	OnRecovered func(charge Charge)
+	// попытки исчерпаны или ошибка неисправима
+	OnFailed func(charge Charge, err error)
+	// ошибки хранилища и временные ошибки SOM в Run; списание будет повторено с тем же ключом
+	OnError func(err error)
+}
+
+func New(store Store, client Client, policy Policy) *Engine {
+	return &Engine{
+		store:     store,
+		client:    client,
+		policy:    policy,
+		Interval:  defaultInterval,
+		Lease:     defaultLease,
+		BatchSize: defaultBatchSize,
+	}
+}
+
+// Charge выполняет первое списание. Если оно отклонено, списание сохраняется в Store
+// для повторов и возвращается ошибка первой попытки. id — стабильный идентификатор
+// списания (например, подписка и период), из него строятся ключи идемпотентности попыток.
+func (e *Engine) Charge(ctx context.Context, id string, request softline.MakePaymentReq) (*softline.CreatePaymentResp, error) {
+	now := time.Now()
+	charge := Charge{
+		ID:        id,
+		Request:   request,
+		State:     StateRetrying,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	response, err := e.attempt(ctx, &charge)
+	if err == nil {
+		return response, nil
+	}
+
+	if saveErr := e.afterFailure(ctx, charge, err); saveErr != nil {
+		return response, errors.Join(err, saveErr)
+	}
+	return response, err
+}
+
+// Run обрабатывает повторы каждые Interval, пока не отменён ctx.
+func (e *Engine) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := e.ProcessDue(ctx); err != nil && ctx.Err() == nil && e.OnError != nil {
+			e.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue выполняет повторы, время которых пришло, и возвращает число восстановленных списаний.
+func (e *Engine) ProcessDue(ctx context.Context) (int, error) {
+	charges, err := e.store.Claim(ctx, time.Now(), e.Lease, e.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("dunning: can't claim charges: %w", err)
+	}
+
+	var recovered int
+	var errs []error
+	for _, charge := range charges {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if _, err := e.attempt(ctx, &charge); err != nil {
+			if saveErr := e.afterFailure(ctx, charge, err); saveErr != nil {
+				errs = append(errs, saveErr)
+			}
+			if !declined(err) && !softline.IsPermanent(err) {
+				errs = append(errs, fmt.Errorf("dunning: charge %s: %w", charge.ID, err))
+			}
+			continue
+		}
+
+		charge.State = StateRecovered
+		if err := e.store.Delete(ctx, charge.ID); err != nil {
+			errs = append(errs, fmt.Errorf("dunning: can't delete %s: %w", charge.ID, err))
+		}
+		recovered++
+		if e.OnRecovered != nil {
+			e.OnRecovered(charge)
+		}
+	}
+	return recovered, errors.Join(errs...)
+}
+
+// attempt выполняет одну попытку; отклонение платежа со статусом 200 тоже считается ошибкой.
+func (e *Engine) attempt(ctx context.Context, charge *Charge) (*softline.CreatePaymentResp, error) {
+	charge.UpdatedAt = time.Now()
+
+	request := charge.Request
+	// ключ меняется только после отклонения: повтор после таймаута или сбоя процесса
+	// идёт с тем же ключом, и списание, уже принятое SOM, не повторится
+	request.IdempotencyKey = fmt.Sprintf("%s:%d", charge.ID, charge.Attempts+1)
+
+	_, response, err := e.client.MakePayment(ctx, request, "")
+	if response != nil {
+		charge.LastStatus = response.Status
+	}
+	if err == nil && response != nil && response.Status == softline.StatusDeclined {
+		err = ErrDeclined
+	}
+	return response, err
+}
+
+func (e *Engine) afterFailure(ctx context.Context, charge Charge, err error) error {
+	charge.LastError = err.Error()
+
+	switch {
+	case declined(err):
+		charge.Attempts++
+		if charge.Attempts > len(e.policy.Delays) {
+			return e.fail(ctx, charge, err)
+		}
+		charge.NextAttemptAt = time.Now().Add(e.policy.Delays[charge.Attempts-1])
+	case softline.IsPermanent(err):
+		return e.fail(ctx, charge, err)
+	default:
+		// исход неизвестен (таймаут, 5xx, сеть): повторяем при следующей обработке с тем же ключом
+		charge.NextAttemptAt = time.Now()
+	}
+
+	charge.State = StateRetrying
+	if saveErr := e.store.Save(ctx, charge); saveErr != nil {
+		return fmt.Errorf("dunning: can't save %s: %w", charge.ID, saveErr)
+	}
+	return nil
+}
+
+func (e *Engine) fail(ctx context.Context, charge Charge, err error) error {
+	charge.State = StateFailed
+	if e.OnFailed != nil {
+		e.OnFailed(charge, err)
+	}
+	if delErr := e.store.Delete(ctx, charge.ID); delErr != nil {
+		return fmt.Errorf("dunning: can't delete %s: %w", charge.ID, delErr)
+	}
+	return nil
+}
+
+// declined — SOM отклонил списание, следующая попытка — по расписанию Policy с новым ключом.
+func declined(err error) bool {
+	return errors.Is(err, ErrDeclined)
+}
@@ -0,0 +1,168 @@
+package dunning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	softline "github.com/dwnGnL/softlinePayment"
+)
+
+// fakeClient отвечает по очереди заданными результатами и запоминает ключи идемпотентности.
+type fakeClient struct {
+	results []error
+	keys    []string
+}
+
+func (c *fakeClient) MakePayment(_ context.Context, data softline.MakePaymentReq, _ string, _ ...softline.RequestOption) ([]byte, *softline.CreatePaymentResp, error) {
+	c.keys = append(c.keys, data.IdempotencyKey)
+
+	err := c.results[0]
+	c.results = c.results[1:]
+	switch {
+	case errors.Is(err, ErrDeclined):
+		return nil, &softline.CreatePaymentResp{OrderId: 1, Status: softline.StatusDeclined}, nil
+	case err != nil:
+		return nil, new(softline.CreatePaymentResp), err
+	}
+	return nil, &softline.CreatePaymentResp{OrderId: 1, Status: softline.StatusPaid}, nil
+}
+
+var errTimeout = fmt.Errorf("can't do request! Err: %w", context.DeadlineExceeded)
+
+func testPolicy() Policy {
+	return Policy{Delays: []time.Duration{time.Hour, 2 * time.Hour}}
+}
+
+func TestDeclineSchedulesNextAttempt(t *testing.T) {
+	store := NewMemoryStore()
+	client := &fakeClient{results: []error{ErrDeclined}}
+	engine := New(store, client, testPolicy())
+
+	if _, err := engine.Charge(context.Background(), "sub:1", softline.MakePaymentReq{ParentOrderId: 1}); !errors.Is(err, ErrDeclined) {
+		t.Fatalf("Charge = %v, want %v", err, ErrDeclined)
+	}
+
+	charge, ok := store.Get("sub:1")
+	if !ok {
+		t.Fatal("declined charge was not saved")
+	}
+	if charge.Attempts != 1 {
+		t.Fatalf("attempts = %d, want 1", charge.Attempts)
+	}
+	if wait := time.Until(charge.NextAttemptAt); wait < 59*time.Minute || wait > time.Hour {
+		t.Fatalf("next attempt in %s, want about 1h", wait)
+	}
+}
+
+func TestTransientErrorKeepsKey(t *testing.T) {
+	store := NewMemoryStore()
+	client := &fakeClient{results: []error{errTimeout, nil}}
+	engine := New(store, client, testPolicy())
+
+	recoveredCalls := 0
+	engine.OnRecovered = func(Charge) { recoveredCalls++ }
+
+	if _, err := engine.Charge(context.Background(), "sub:1", softline.MakePaymentReq{ParentOrderId: 1}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Charge = %v, want timeout", err)
+	}
+
+	charge, ok := store.Get("sub:1")
+	if !ok {
+		t.Fatal("charge with unknown outcome was not saved")
+	}
+	if charge.Attempts != 0 {
+		t.Fatalf("attempts = %d, want 0: timeout is not a decline", charge.Attempts)
+	}
+	if charge.NextAttemptAt.After(time.Now()) {
+		t.Fatalf("next attempt at %s, want immediate retry", charge.NextAttemptAt)
+	}
+
+	recovered, err := engine.ProcessDue(context.Background())
+	if err != nil || recovered != 1 || recoveredCalls != 1 {
+		t.Fatalf("ProcessDue = %d, %v; OnRecovered calls %d", recovered, err, recoveredCalls)
+	}
+	if len(client.keys) != 2 || client.keys[0] != client.keys[1] {
+		t.Fatalf("idempotency keys = %v, want the same key for the retry", client.keys)
+	}
+}
+
+func TestTransientErrorReportedByProcessDue(t *testing.T) {
+	store := NewMemoryStore()
+	client := &fakeClient{results: []error{ErrDeclined, softline.ErrServer}}
+	engine := New(store, client, Policy{Delays: []time.Duration{0}})
+
+	_, _ = engine.Charge(context.Background(), "sub:1", softline.MakePaymentReq{ParentOrderId: 1})
+	if _, err := engine.ProcessDue(context.Background()); !errors.Is(err, softline.ErrServer) {
+		t.Fatalf("ProcessDue = %v, want %v", err, softline.ErrServer)
+	}
+
+	charge, _ := store.Get("sub:1")
+	if charge.Attempts != 1 {
+		t.Fatalf("attempts = %d, want 1", charge.Attempts)
+	}
+	if client.keys[1] != "sub:1:2" {
+		t.Fatalf("retry key = %q, want sub:1:2", client.keys[1])
+	}
+}
+
+func TestAttemptsExhausted(t *testing.T) {
+	store := NewMemoryStore()
+	client := &fakeClient{results: []error{ErrDeclined, ErrDeclined}}
+	engine := New(store, client, Policy{Delays: []time.Duration{0}})
+
+	var failed []Charge
+	engine.OnFailed = func(charge Charge, _ error) { failed = append(failed, charge) }
+
+	_, _ = engine.Charge(context.Background(), "sub:1", softline.MakePaymentReq{ParentOrderId: 1})
+	if _, err := engine.ProcessDue(context.Background()); err != nil {
+		t.Fatalf("ProcessDue: %v", err)
+	}
+
+	if len(failed) != 1 || failed[0].State != StateFailed || failed[0].Attempts != 2 {
+		t.Fatalf("failed = %+v, want one failed charge after 2 attempts", failed)
+	}
+	if _, ok := store.Get("sub:1"); ok {
+		t.Fatal("failed charge is still in store")
+	}
+	if client.keys[0] != "sub:1:1" || client.keys[1] != "sub:1:2" {
+		t.Fatalf("keys = %v", client.keys)
+	}
+}
+
+func TestPermanentErrorFails(t *testing.T) {
+	store := NewMemoryStore()
+	client := &fakeClient{results: []error{softline.ErrValidation}}
+	engine := New(store, client, testPolicy())
+
+	failed := 0
+	engine.OnFailed = func(Charge, error) { failed++ }
+
+	_, _ = engine.Charge(context.Background(), "sub:1", softline.MakePaymentReq{ParentOrderId: 1})
+	if failed != 1 {
+		t.Fatalf("OnFailed calls = %d, want 1", failed)
+	}
+	if _, ok := store.Get("sub:1"); ok {
+		t.Fatal("failed charge is still in store")
+	}
+}
+
+func TestUnauthorizedIsTransient(t *testing.T) {
+	store := NewMemoryStore()
+	client := &fakeClient{results: []error{softline.ErrUnauthorized}}
+	engine := New(store, client, testPolicy())
+
+	failed := 0
+	engine.OnFailed = func(Charge, error) { failed++ }
+
+	_, _ = engine.Charge(context.Background(), "sub:1", softline.MakePaymentReq{ParentOrderId: 1})
+	if failed != 0 {
+		t.Fatalf("OnFailed calls = %d, want 0", failed)
+	}
+	charge, ok := store.Get("sub:1")
+	if !ok || charge.Attempts != 0 {
+		t.Fatalf("charge = %+v, %v; want a pending retry without a counted attempt", charge, ok)
+	}
+}
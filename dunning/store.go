@@ -0,0 +1,52 @@
+package dunning
+
+import (
+	"context"
+	"time"
+
+	"github.com/dwnGnL/softlinePayment/internal/lease"
+)
+
+// Store хранит списания, ожидающие повтора. Для работы между перезапусками реализация должна быть персистентной.
+type Store interface {
+	Save(ctx context.Context, charge Charge) error
+	// Claim атомарно выбирает до limit активных списаний с NextAttemptAt <= now
+	// и переносит их NextAttemptAt на now+lease, чтобы их не взял другой обработчик.
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Charge, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// MemoryStore — Store в памяти процесса для тестов и разработки.
+type MemoryStore struct {
+	charges *lease.Store[Charge]
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		charges: lease.NewStore(lease.Rules[Charge]{
+			ID:    func(charge Charge) string { return charge.ID },
+			DueAt: func(charge Charge) time.Time { return charge.NextAttemptAt },
+			Ready: func(charge Charge, _ time.Time) bool { return charge.State == StateRetrying },
+			Lease: func(charge *Charge, until time.Time) { charge.NextAttemptAt = until },
+		}),
+	}
+}
+
+func (m *MemoryStore) Save(_ context.Context, charge Charge) error {
+	m.charges.Put(charge)
+	return nil
+}
+
+func (m *MemoryStore) Claim(_ context.Context, now time.Time, lease time.Duration, limit int) ([]Charge, error) {
+	return m.charges.Claim(now, lease, limit), nil
+}
+
+func (m *MemoryStore) Delete(_ context.Context, id string) error {
+	m.charges.Delete(id)
+	return nil
+}
+
+// Get возвращает списание по идентификатору.
+func (m *MemoryStore) Get(id string) (Charge, bool) {
+	return m.charges.Get(id)
+}
@@ -0,0 +1,56 @@
+package softlinePayment
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+type Environment string
+
+const (
+	EnvSandbox    Environment = "sandbox"
+	EnvProduction Environment = "production"
+)
+
+const (
+	SandboxURI    = "https://sandbox.api.softlinepayment.com"
+	ProductionURI = "https://api.softlinepayment.com"
+)
+
+var ErrEnvironmentMismatch = errors.New("softline: sandbox environment must not send charges to production")
+
+// BaseURI возвращает адрес API для окружения.
+func (e Environment) BaseURI() string {
+	switch e {
+	case EnvSandbox:
+		return SandboxURI
+	case EnvProduction:
+		return ProductionURI
+	}
+	return ""
+}
+
+// guardEnvironment не даёт окружению Sandbox отправлять изменяющие запросы на боевой хост.
+func guardEnvironment(env Environment, target *url.URL, method string) error {
+	if env != EnvSandbox || isSafeMethod(method) {
+		return nil
+	}
+
+	production, err := url.Parse(ProductionURI)
+	if err != nil {
+		return nil
+	}
+	if strings.EqualFold(target.Hostname(), production.Hostname()) {
+		return ErrEnvironmentMismatch
+	}
+	return nil
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	}
+	return false
+}
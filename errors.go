@@ -0,0 +1,230 @@
+package softlinePayment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnauthorized = errors.New("softline: unauthorized")
+	ErrNotFound     = errors.New("softline: not found")
+	ErrValidation   = errors.New("softline: validation failed")
+	ErrServer       = errors.New("softline: server error")
+	ErrRateLimited  = errors.New("softline: rate limited")
+	ErrRejected     = errors.New("softline: request rejected") // бизнес-ошибка в ответе 2xx
+)
+
+// APIError — ответ SOM с кодом статуса, отличным от 2xx.
+type APIError struct {
+	HTTPStatus int
+	Code       int
+	Message    string
+	Errors     []Error
+	Body       []byte
+	RequestID  string
+}
+
+func newAPIError(status int, body []byte) *APIError {
+	apiErr := &APIError{
+		HTTPStatus: status,
+		Body:       body,
+	}
+
+	var parsed struct {
+		Errors []Error `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &parsed); err == nil && len(parsed.Errors) > 0 {
+		apiErr.Errors = parsed.Errors
+		apiErr.Code = parsed.Errors[0].Error
+		apiErr.Message = parsed.Errors[0].Message
+	}
+
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(status)
+	}
+
+	return apiErr
+}
+
+// newEnvelopeError ищет ошибку в теле успешного ответа: {"errors": [...]}, {"error": {"code", "message"}},
+// {"error": "..."} или {"success": false, "code", "message"}. nil — ответ не содержит ошибки.
+func newEnvelopeError(status int, body []byte) *APIError {
+	var envelope struct {
+		Success *bool           `json:"success"`
+		Code    json.RawMessage `json:"code"`
+		Message string          `json:"message"`
+		Error   json.RawMessage `json:"error"`
+		Errors  []Error         `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil
+	}
+
+	apiErr := &APIError{
+		HTTPStatus: status,
+		Body:       body,
+	}
+
+	switch {
+	case len(envelope.Errors) > 0:
+		apiErr.Errors = envelope.Errors
+		apiErr.Code = envelope.Errors[0].Error
+		apiErr.Message = envelope.Errors[0].Message
+	case envelopeFlagged(envelope.Error):
+		var detail struct {
+			Code    json.RawMessage `json:"code"`
+			Message string          `json:"message"`
+		}
+		var text string
+		if err := json.Unmarshal(envelope.Error, &detail); err == nil {
+			apiErr.Code, apiErr.Message = envelopeCode(detail.Code, detail.Message)
+		} else if err := json.Unmarshal(envelope.Error, &text); err == nil {
+			apiErr.Message = text
+		} else {
+			// "error": true — код и сообщение лежат рядом, на верхнем уровне
+			apiErr.Code, apiErr.Message = envelopeCode(envelope.Code, envelope.Message)
+		}
+		if apiErr.Message == "" {
+			apiErr.Message = envelope.Message
+		}
+	case envelope.Success != nil && !*envelope.Success:
+		apiErr.Code, apiErr.Message = envelopeCode(envelope.Code, envelope.Message)
+	default:
+		return nil
+	}
+
+	if apiErr.Message == "" {
+		apiErr.Message = "request rejected"
+	}
+	return apiErr
+}
+
+// envelopeFlagged сообщает, что поле "error" означает ошибку: объект, непустая строка или true.
+// false, 0, "" и null SOM присылает и в успешных ответах.
+func envelopeFlagged(raw json.RawMessage) bool {
+	var value interface{}
+	if len(raw) == 0 || json.Unmarshal(raw, &value) != nil {
+		return false
+	}
+	switch value := value.(type) {
+	case map[string]interface{}:
+		return true
+	case string:
+		return value != ""
+	case bool:
+		return value
+	}
+	return false
+}
+
+// envelopeCode разбирает код ошибки: числовой кладётся в Code, строковый — в начало сообщения.
+func envelopeCode(raw json.RawMessage, message string) (int, string) {
+	var code int
+	if err := json.Unmarshal(raw, &code); err == nil {
+		return code, message
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil && text != "" {
+		if n, err := strconv.Atoi(text); err == nil {
+			return n, message
+		}
+		if message == "" {
+			return 0, text
+		}
+		return 0, text + ": " + message
+	}
+	return 0, message
+}
+
+func (e *APIError) Error() string {
+	if len(e.Errors) > 1 {
+		messages := make([]string, 0, len(e.Errors))
+		for _, item := range e.Errors {
+			messages = append(messages, fmt.Sprintf("%d: %s", item.Error, item.Message))
+		}
+		return fmt.Sprintf("softline api error: status %d: %s", e.HTTPStatus, strings.Join(messages, "; "))
+	}
+
+	if e.Code != 0 {
+		return fmt.Sprintf("softline api error: status %d, code %d: %s", e.HTTPStatus, e.Code, e.Message)
+	}
+
+	return fmt.Sprintf("softline api error: status %d: %s", e.HTTPStatus, e.Message)
+}
+
+// ErrorBody возвращает сырое тело ответа SOM.
+func (e *APIError) ErrorBody() []byte {
+	return e.Body
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.HTTPStatus == http.StatusUnauthorized || e.HTTPStatus == http.StatusForbidden
+	case ErrNotFound:
+		return e.HTTPStatus == http.StatusNotFound
+	case ErrValidation:
+		return e.HTTPStatus == http.StatusBadRequest || e.HTTPStatus == http.StatusUnprocessableEntity
+	case ErrRejected:
+		return e.HTTPStatus < http.StatusBadRequest
+	case ErrRateLimited:
+		return e.HTTPStatus == http.StatusTooManyRequests
+	case ErrServer:
+		return e.HTTPStatus >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// IsPermanent сообщает, что повтор запроса с теми же данными не поможет: SOM отклонил его
+// как некорректный, не нашёл заказ, отказал по бизнес-правилам или возврат уже выполнен.
+// Ошибки авторизации, лимитов, 5xx и сети считаются временными.
+func IsPermanent(err error) bool {
+	return errors.Is(err, ErrValidation) ||
+		errors.Is(err, ErrNotFound) ||
+		errors.Is(err, ErrRejected) ||
+		errors.Is(err, ErrAlreadyRefunded)
+}
+
+// RateLimitError — ответ 429 от SOM. RetryAfter — задержка из заголовка Retry-After (0, если его нет).
+type RateLimitError struct {
+	*APIError
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s (retry after %s)", e.APIError.Error(), e.RetryAfter)
+	}
+	return e.APIError.Error()
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return e.APIError
+}
+
+// DecodeError — успешный по статусу ответ SOM, который не удалось разобрать.
+type DecodeError struct {
+	Operation  string
+	HTTPStatus int
+	Body       []byte
+	Err        error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("softline! %s: can't unmarshall response: '%s'. Err: %v", e.Operation, e.Body, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorBody возвращает сырое тело ответа SOM.
+func (e *DecodeError) ErrorBody() []byte {
+	return e.Body
+}
@@ -0,0 +1,80 @@
+package softlinePayment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestIsPermanent(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"validation", newAPIError(http.StatusBadRequest, nil), true},
+		{"unprocessable", newAPIError(http.StatusUnprocessableEntity, nil), true},
+		{"not found", newAPIError(http.StatusNotFound, nil), true},
+		{"rejected", newAPIError(http.StatusOK, nil), true},
+		{"already refunded", fmt.Errorf("%w: order 1 is refunded", ErrAlreadyRefunded), true},
+		{"unauthorized", newAPIError(http.StatusUnauthorized, nil), false},
+		{"forbidden", newAPIError(http.StatusForbidden, nil), false},
+		{"rate limited", newAPIError(http.StatusTooManyRequests, nil), false},
+		{"server", newAPIError(http.StatusBadGateway, nil), false},
+		{"timeout", fmt.Errorf("can't do request! Err: %w", context.DeadlineExceeded), false},
+		{"circuit open", ErrCircuitOpen, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPermanent(tt.err); got != tt.want {
+				t.Fatalf("IsPermanent(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewEnvelopeError(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+		code    int
+		message string
+	}{
+		{name: "plain response", body: `{"order_id": 1, "status": "paid"}`},
+		{name: "not an object", body: `[1, 2]`},
+		{name: "error null", body: `{"order_id": 1, "error": null}`},
+		{name: "error false", body: `{"order_id": 1, "error": false}`},
+		{name: "error zero", body: `{"order_id": 1, "error": 0}`},
+		{name: "error empty string", body: `{"order_id": 1, "error": ""}`},
+		{name: "success true", body: `{"success": true, "error": false}`},
+		{name: "error false with success false", body: `{"success": false, "error": false, "code": 12, "message": "declined"}`, wantErr: true, code: 12, message: "declined"},
+		{name: "error true", body: `{"error": true, "code": "7", "message": "insufficient funds"}`, wantErr: true, code: 7, message: "insufficient funds"},
+		{name: "error string", body: `{"error": "order is locked"}`, wantErr: true, message: "order is locked"},
+		{name: "error object", body: `{"error": {"code": 5, "message": "bad card"}}`, wantErr: true, code: 5, message: "bad card"},
+		{name: "error object string code", body: `{"error": {"code": "card_expired"}}`, wantErr: true, message: "card_expired"},
+		{name: "error empty object", body: `{"error": {}, "message": "rejected by bank"}`, wantErr: true, message: "rejected by bank"},
+		{name: "errors list", body: `{"errors": [{"error": 3, "message": "wrong amount"}]}`, wantErr: true, code: 3, message: "wrong amount"},
+		{name: "success false without message", body: `{"success": false}`, wantErr: true, message: "request rejected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := newEnvelopeError(http.StatusOK, []byte(tt.body))
+			if !tt.wantErr {
+				if apiErr != nil {
+					t.Fatalf("newEnvelopeError = %v, want nil", apiErr)
+				}
+				return
+			}
+			if apiErr == nil {
+				t.Fatal("newEnvelopeError = nil, want error")
+			}
+			if apiErr.Code != tt.code || apiErr.Message != tt.message {
+				t.Fatalf("code, message = %d, %q; want %d, %q", apiErr.Code, apiErr.Message, tt.code, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package softlinePayment
+
+import (
+	"sync"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventTokenRefreshed   EventType = "token.refreshed"
+	EventRequestRetried   EventType = "request.retried"
+	EventCircuitChanged   EventType = "circuit.changed"
+	EventWebhookReceived  EventType = "webhook.received"
+	EventPaymentCompleted EventType = "payment.terminal"
+)
+
+// LifecycleEvent — событие жизненного цикла SDK. Заполнены только поля, относящиеся к Type.
+type LifecycleEvent struct {
+	Type      EventType
+	Time      time.Time
+	Operation string
+	Attempt   int
+	Err       error
+	// EventCircuitChanged
+	CircuitFrom CircuitState
+	CircuitTo   CircuitState
+	// EventWebhookReceived, EventPaymentCompleted
+	Payment *PaymentResp
+}
+
+// EventBus рассылает LifecycleEvent подписчикам. Обработчики вызываются синхронно
+// и должны быть быстрыми; nil *EventBus события молча отбрасывает.
+type EventBus struct {
+	mu          sync.RWMutex
+	subscribers map[int]subscriber
+	nextID      int
+}
+
+type subscriber struct {
+	types   map[EventType]struct{}
+	handler func(event LifecycleEvent)
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{
+		subscribers: make(map[int]subscriber),
+	}
+}
+
+// Subscribe регистрирует обработчик событий перечисленных типов (без типов — всех).
+// Возвращает функцию отписки.
+func (b *EventBus) Subscribe(handler func(event LifecycleEvent), types ...EventType) (unsubscribe func()) {
+	sub := subscriber{handler: handler}
+	if len(types) > 0 {
+		sub.types = make(map[EventType]struct{}, len(types))
+		for _, t := range types {
+			sub.types[t] = struct{}{}
+		}
+	}
+
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = sub
+	b.mu.Unlock()
+
+	return func() {
+		b.mu.Lock()
+		delete(b.subscribers, id)
+		b.mu.Unlock()
+	}
+}
+
+// Publish передаёт событие подписчикам; пустое Time заполняется текущим временем.
+func (b *EventBus) Publish(event LifecycleEvent) {
+	if b == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	handlers := make([]func(LifecycleEvent), 0, len(b.subscribers))
+	for _, sub := range b.subscribers {
+		if _, ok := sub.types[event.Type]; sub.types == nil || ok {
+			handlers = append(handlers, sub.handler)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// WithEventBus подключает EventBus для событий жизненного цикла Service.
+func WithEventBus(bus *EventBus) Option {
+	return func(s *Service) {
+		s.events = bus
+	}
+}
+
+// Events возвращает EventBus, заданный WithEventBus, или nil.
+func (s *Service) Events() *EventBus {
+	return s.events
+}
@@ -0,0 +1,99 @@
+package softlinePayment
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// doFailover отправляет запрос на основной адрес SOM, а если тот недоступен —
+// по очереди на резервные из Config.FallbackURIs. Ответ сервера, даже 5xx, считается доступностью.
+// Неидемпотентные запросы переключаются, только если соединение не было установлено.
+func (s *Service) doFailover(ctx context.Context, finalUrls []string, reqBody *requestBody, inputs *SendParams, canFailover bool) (resp *http.Response, respBody []byte, err error) {
+	if hedgeDelay := s.current().hedgeDelay; hedgeDelay > 0 && inputs.HttpMethod == http.MethodGet && len(finalUrls) > 1 && (reqBody == nil || reqBody.stream == nil) {
+		return s.doHedged(ctx, finalUrls, reqBody, inputs, hedgeDelay)
+	}
+
+	for i, finalUrl := range finalUrls {
+		resp, respBody, err = s.doRequest(ctx, finalUrl, reqBody, inputs)
+		if !isUnreachable(ctx, err) || (!canFailover && !isDialError(err)) || !reqBody.replayable() || i == len(finalUrls)-1 {
+			return
+		}
+		s.logger.Warnf("softline host unreachable for %s %s, failing over: %v", inputs.HttpMethod, inputs.Path, err)
+		if s.connRecycler != nil {
+			// соединения с недоступным хостом больше не переиспользуются
+			s.closeIdleConns()
+		}
+	}
+	return
+}
+
+// doHedged для GET-запросов: если адрес не ответил за hedgeDelay, параллельно
+// запрашивается следующий. Возвращается первый ответ без ошибки транспорта и 5xx.
+func (s *Service) doHedged(ctx context.Context, finalUrls []string, reqBody *requestBody, inputs *SendParams, hedgeDelay time.Duration) (*http.Response, []byte, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		resp     *http.Response
+		respBody []byte
+		err      error
+	}
+	results := make(chan result, len(finalUrls))
+
+	launched := 0
+	launch := func() {
+		finalUrl := finalUrls[launched]
+		launched++
+		go func() {
+			resp, respBody, err := s.doRequest(ctx, finalUrl, reqBody, inputs)
+			results <- result{resp, respBody, err}
+		}()
+	}
+
+	timer := time.NewTimer(hedgeDelay)
+	defer timer.Stop()
+
+	launch()
+	var last result
+	for received := 0; received < len(finalUrls); {
+		select {
+		case <-timer.C:
+			if launched < len(finalUrls) {
+				launch()
+				timer.Reset(hedgeDelay)
+			}
+		case r := <-results:
+			received++
+			if r.err == nil && r.resp.StatusCode < http.StatusInternalServerError {
+				return r.resp, r.respBody, nil
+			}
+			last = r
+			if launched < len(finalUrls) {
+				launch()
+				timer.Reset(hedgeDelay)
+			} else if received == launched {
+				return last.resp, last.respBody, last.err
+			}
+		}
+	}
+	return last.resp, last.respBody, last.err
+}
+
+// isUnreachable — ошибка соединения с хостом, а не отмена вызывающим и не отказ предохранителя.
+func isUnreachable(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// isDialError — соединение не установлено, значит запрос точно не дошёл до SOM.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
@@ -0,0 +1,144 @@
+package softlinePayment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// hostServer — SOM-хост, считающий обращения.
+type hostServer struct {
+	*httptest.Server
+	calls atomic.Int32
+}
+
+func newHost(t *testing.T, status int, delay time.Duration) *hostServer {
+	t.Helper()
+
+	host := &hostServer{}
+	host.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host.calls.Add(1)
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"order_id": 42, "status": "paid"}`))
+	}))
+	t.Cleanup(host.Close)
+	return host
+}
+
+// unreachableURL — адрес, на котором соединение отклоняется.
+func unreachableURL() string {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	return server.URL
+}
+
+func newFailoverService(t *testing.T, config Config) *Service {
+	t.Helper()
+
+	config.Login, config.Pass = "login", "pass"
+	s, err := New(&config)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func TestFailoverOnUnreachableHost(t *testing.T) {
+	fallback := newHost(t, http.StatusOK, 0)
+	s := newFailoverService(t, Config{URI: unreachableURL(), FallbackURIs: []string{fallback.URL}})
+
+	if _, _, err := s.PostCheck(context.Background(), "42", "token"); err != nil {
+		t.Fatalf("PostCheck: %v", err)
+	}
+	// соединение не установлено, значит и неидемпотентный запрос можно отправить на резервный хост
+	if _, _, err := s.Capture(context.Background(), CaptureReq{OrderID: "42"}, "token"); err != nil {
+		t.Fatalf("Capture: %v", err)
+	}
+	if n := fallback.calls.Load(); n != 2 {
+		t.Fatalf("fallback calls = %d, want 2", n)
+	}
+}
+
+func TestNoFailoverOnServerError(t *testing.T) {
+	primary := newHost(t, http.StatusInternalServerError, 0)
+	fallback := newHost(t, http.StatusOK, 0)
+	s := newFailoverService(t, Config{URI: primary.URL, FallbackURIs: []string{fallback.URL}})
+
+	if _, _, err := s.PostCheck(context.Background(), "42", "token"); !errors.Is(err, ErrServer) {
+		t.Fatalf("PostCheck = %v, want %v", err, ErrServer)
+	}
+	if n := fallback.calls.Load(); n != 0 {
+		t.Fatalf("fallback calls = %d, want 0: 5xx means the host is reachable", n)
+	}
+}
+
+func TestHedgedRequestToSlowHost(t *testing.T) {
+	primary := newHost(t, http.StatusOK, time.Second)
+	fallback := newHost(t, http.StatusOK, 0)
+	s := newFailoverService(t, Config{URI: primary.URL, FallbackURIs: []string{fallback.URL}, HedgeDelayMs: 10})
+
+	started := time.Now()
+	if _, _, err := s.PostCheck(context.Background(), "42", "token"); err != nil {
+		t.Fatalf("PostCheck: %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+		t.Fatalf("hedged request took %s, want the fallback answer", elapsed)
+	}
+	if primary.calls.Load() != 1 || fallback.calls.Load() != 1 {
+		t.Fatalf("calls = %d primary, %d fallback; want 1 each", primary.calls.Load(), fallback.calls.Load())
+	}
+}
+
+func TestHedgedRequestAfterServerError(t *testing.T) {
+	primary := newHost(t, http.StatusBadGateway, 0)
+	fallback := newHost(t, http.StatusOK, 0)
+	s := newFailoverService(t, Config{URI: primary.URL, FallbackURIs: []string{fallback.URL}, HedgeDelayMs: int(time.Minute / time.Millisecond)})
+
+	// 5xx не ждёт hedgeDelay: следующий хост запрашивается сразу
+	if _, _, err := s.PostCheck(context.Background(), "42", "token"); err != nil {
+		t.Fatalf("PostCheck: %v", err)
+	}
+}
+
+func TestNoHedgingForPost(t *testing.T) {
+	primary := newHost(t, http.StatusOK, 50*time.Millisecond)
+	fallback := newHost(t, http.StatusOK, 0)
+	s := newFailoverService(t, Config{URI: primary.URL, FallbackURIs: []string{fallback.URL}, HedgeDelayMs: 1})
+
+	if _, _, err := s.Capture(context.Background(), CaptureReq{OrderID: "42"}, "token"); err != nil {
+		t.Fatalf("Capture: %v", err)
+	}
+	if n := fallback.calls.Load(); n != 0 {
+		t.Fatalf("fallback calls = %d, want 0", n)
+	}
+}
+
+func TestIsUnreachable(t *testing.T) {
+	s := newFailoverService(t, Config{URI: unreachableURL()})
+	_, _, err := s.PostCheck(context.Background(), "42", "token")
+	if err == nil {
+		t.Fatal("PostCheck succeeded on an unreachable host")
+	}
+
+	if !isUnreachable(context.Background(), err) || !isDialError(err) {
+		t.Fatalf("connection refused is not treated as unreachable: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if isUnreachable(ctx, err) {
+		t.Fatal("error after caller cancellation is treated as unreachable")
+	}
+	if isUnreachable(context.Background(), ErrCircuitOpen) {
+		t.Fatal("open circuit is treated as unreachable")
+	}
+}
@@ -0,0 +1,45 @@
+package softlinePayment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const fxQuote = "/v1/fx/quote"
+
+// GetFxQuote запрашивает курс конвертации суммы из валюты From в To. Эндпоинт доступен,
+// если конвертация включена для мерчанта. QuoteId можно передать в CreatePaymentReq.FxQuoteId,
+// чтобы платёж прошёл по зафиксированному курсу до ExpiresAt.
+func (s *Service) GetFxQuote(ctx context.Context, request FxQuoteReq, token string, opts ...RequestOption) (respBody []byte, response *FxQuote, err error) {
+	if err = ValidateAmount(request.Amount, request.From); err != nil {
+		return nil, new(FxQuote), err
+	}
+	if _, ok := CurrencyExponent(request.To); !ok {
+		return nil, new(FxQuote), fmt.Errorf("%w: %w: unknown ISO 4217 code %q", ErrValidation, ErrInvalidCurrency, request.To)
+	}
+	if strings.EqualFold(request.From, request.To) {
+		return nil, new(FxQuote), fmt.Errorf("%w: %w", ErrValidation, errors.New("from and to currencies must differ"))
+	}
+
+	query := queryParams{}.
+		Set("from", strings.ToUpper(request.From)).
+		Set("to", strings.ToUpper(request.To)).
+		Set("amount", request.Amount.String())
+
+	return call[struct{}, FxQuote](ctx, s, &SendParams{
+		Operation:   "get_fx_quote",
+		Path:        fxQuote,
+		HttpMethod:  http.MethodGet,
+		AuthNeed:    true,
+		QueryParams: query,
+	}, nil, token, opts)
+}
+
+// Expired — курс больше не действует, платёж по QuoteId будет отклонён.
+func (q *FxQuote) Expired(now time.Time) bool {
+	return !q.ExpiresAt.IsZero() && !now.Before(q.ExpiresAt)
+}
@@ -0,0 +1,82 @@
+package softlinePayment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const (
+	installmentPlans      = "/v1/installment"
+	installmentPlan       = "/v1/installment/%s"
+	payOffInstallmentPlan = "/v1/installment/%s/payoff"
+)
+
+const minInstallmentPayments = 2
+
+// CreateInstallmentPlan создаёт план рассрочки: Amount делится на Count платежей с периодом Interval.
+func (s *Service) CreateInstallmentPlan(ctx context.Context, data CreateInstallmentPlanReq, token string, opts ...RequestOption) (respBody []byte, response *InstallmentPlan, err error) {
+	if err = validateInstallmentCount(data.Count); err != nil {
+		return nil, new(InstallmentPlan), err
+	}
+	if data.Amount.IsZero() || data.Amount.IsNegative() {
+		return nil, new(InstallmentPlan), fmt.Errorf("%w: %w", ErrValidation, errors.New("installment amount must be positive"))
+	}
+
+	inputs := &SendParams{
+		Operation:      "create_installment_plan",
+		Path:           installmentPlans,
+		HttpMethod:     http.MethodPost,
+		AuthNeed:       true,
+		Idempotent:     true,
+		IdempotencyKey: data.IdempotencyKey,
+	}
+	respBody, response, err = call[CreateInstallmentPlanReq, InstallmentPlan](ctx, s, inputs, &data, token, opts)
+	response.IdempotencyKey = inputs.IdempotencyKey
+
+	return
+}
+
+// GetInstallmentSchedule возвращает план рассрочки с графиком платежей.
+func (s *Service) GetInstallmentSchedule(ctx context.Context, planID string, token string, opts ...RequestOption) (respBody []byte, response *InstallmentPlan, err error) {
+	path, err := endpointPath(installmentPlan, planID)
+	if err != nil {
+		return nil, new(InstallmentPlan), err
+	}
+
+	return call[struct{}, InstallmentPlan](ctx, s, &SendParams{
+		Operation:  "get_installment_schedule",
+		Path:       path,
+		HttpMethod: http.MethodGet,
+		AuthNeed:   true,
+	}, nil, token, opts)
+}
+
+// PayOffInstallmentPlan досрочно списывает весь остаток по плану рассрочки.
+func (s *Service) PayOffInstallmentPlan(ctx context.Context, data PayOffInstallmentPlanReq, token string, opts ...RequestOption) (respBody []byte, response *InstallmentPlan, err error) {
+	path, err := endpointPath(payOffInstallmentPlan, data.PlanID)
+	if err != nil {
+		return nil, new(InstallmentPlan), err
+	}
+
+	inputs := &SendParams{
+		Operation:      "pay_off_installment_plan",
+		Path:           path,
+		HttpMethod:     http.MethodPost,
+		AuthNeed:       true,
+		Idempotent:     true,
+		IdempotencyKey: data.IdempotencyKey,
+	}
+	respBody, response, err = call[struct{}, InstallmentPlan](ctx, s, inputs, nil, token, opts)
+	response.IdempotencyKey = inputs.IdempotencyKey
+
+	return
+}
+
+func validateInstallmentCount(count int) error {
+	if count < minInstallmentPayments {
+		return fmt.Errorf("%w: installment count must be at least %d", ErrValidation, minInstallmentPayments)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Package lease — хранилище задач в памяти процесса с захватом на время обработки.
+// На нём построены MemoryStore пакетов dunning, recurring и outbox.
+package lease
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Rules описывает, как Store работает с задачами конкретного типа.
+type Rules[T any] struct {
+	// идентификатор задачи
+	ID func(item T) string
+	// время, по которому задачи выбираются и упорядочиваются
+	DueAt func(item T) time.Time
+	// готова ли задача к обработке без учёта DueAt: активна и не занята другим обработчиком
+	Ready func(item T, now time.Time) bool
+	// занимает задачу до until
+	Lease func(item *T, until time.Time)
+}
+
+// Store хранит задачи по идентификатору. Методы безопасны для конкурентного использования.
+type Store[T any] struct {
+	mu    sync.Mutex
+	rules Rules[T]
+	items map[string]T
+}
+
+func NewStore[T any](rules Rules[T]) *Store[T] {
+	return &Store[T]{
+		rules: rules,
+		items: make(map[string]T),
+	}
+}
+
+func (s *Store[T]) Put(item T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items[s.rules.ID(item)] = item
+}
+
+// Claim атомарно выбирает до limit готовых задач с DueAt <= now, начиная с самых ранних,
+// и занимает их до now+lease. limit <= 0 — без ограничения.
+func (s *Store[T]) Claim(now time.Time, lease time.Duration, limit int) []T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []T
+	for _, item := range s.items {
+		if s.rules.Ready(item, now) && !s.rules.DueAt(item).After(now) {
+			due = append(due, item)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		x, y := s.rules.DueAt(due[i]), s.rules.DueAt(due[j])
+		if x.Equal(y) {
+			return s.rules.ID(due[i]) < s.rules.ID(due[j])
+		}
+		return x.Before(y)
+	})
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+
+	for i := range due {
+		s.rules.Lease(&due[i], now.Add(lease))
+		s.items[s.rules.ID(due[i])] = due[i]
+	}
+	return due
+}
+
+func (s *Store[T]) Delete(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.items, id)
+}
+
+func (s *Store[T]) Get(id string) (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[id]
+	return item, ok
+}
+
+// Filter возвращает задачи, для которых match вернул true.
+func (s *Store[T]) Filter(match func(item T) bool) []T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var items []T
+	for _, item := range s.items {
+		if match(item) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
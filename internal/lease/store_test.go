@@ -0,0 +1,76 @@
+package lease
+
+import (
+	"testing"
+	"time"
+)
+
+type task struct {
+	id     string
+	active bool
+	at     time.Time
+	until  time.Time
+}
+
+func newTaskStore() *Store[task] {
+	return NewStore(Rules[task]{
+		ID:    func(t task) string { return t.id },
+		DueAt: func(t task) time.Time { return t.at },
+		Ready: func(t task, now time.Time) bool { return t.active && !t.until.After(now) },
+		Lease: func(t *task, until time.Time) { t.until = until },
+	})
+}
+
+func ids(tasks []task) []string {
+	out := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		out = append(out, t.id)
+	}
+	return out
+}
+
+func TestClaimOrderAndLimit(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := newTaskStore()
+	store.Put(task{id: "late", active: true, at: now.Add(-time.Minute)})
+	store.Put(task{id: "early", active: true, at: now.Add(-time.Hour)})
+	store.Put(task{id: "future", active: true, at: now.Add(time.Minute)})
+	store.Put(task{id: "inactive", at: now.Add(-2 * time.Hour)})
+
+	claimed := store.Claim(now, time.Minute, 1)
+	if got := ids(claimed); len(got) != 1 || got[0] != "early" {
+		t.Fatalf("first claim = %v, want [early]", got)
+	}
+	if !claimed[0].until.Equal(now.Add(time.Minute)) {
+		t.Fatalf("lease until = %s, want %s", claimed[0].until, now.Add(time.Minute))
+	}
+
+	// занятая задача не выдаётся повторно, пока не истечёт аренда
+	if got := ids(store.Claim(now, time.Minute, 0)); len(got) != 1 || got[0] != "late" {
+		t.Fatalf("second claim = %v, want [late]", got)
+	}
+	if got := store.Claim(now, time.Minute, 0); len(got) != 0 {
+		t.Fatalf("third claim = %v, want none", ids(got))
+	}
+	if got := ids(store.Claim(now.Add(2*time.Minute), time.Minute, 0)); len(got) != 3 {
+		t.Fatalf("claim after lease = %v, want early, late and future", got)
+	}
+}
+
+func TestGetDeleteFilter(t *testing.T) {
+	store := newTaskStore()
+	store.Put(task{id: "a", active: true})
+	store.Put(task{id: "b"})
+
+	if item, ok := store.Get("a"); !ok || !item.active {
+		t.Fatalf("Get(a) = %+v, %v", item, ok)
+	}
+	if got := store.Filter(func(t task) bool { return !t.active }); len(got) != 1 || got[0].id != "b" {
+		t.Fatalf("Filter = %v, want [b]", ids(got))
+	}
+
+	store.Delete("a")
+	if _, ok := store.Get("a"); ok {
+		t.Fatal("a is still stored after Delete")
+	}
+}
@@ -0,0 +1,104 @@
+package softlinePayment
+
+import (
+	"context"
+	"fmt"
+)
+
+// Iterator обходит все страницы списка SOM, следуя курсору NextCursor:
+//
+//	it := s.IterateOrders(ctx, filter)
+//	for it.Next() {
+//		order := it.Item()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type Iterator[T any] struct {
+	ctx    context.Context
+	fetch  func(ctx context.Context, cursor string) (items []T, next string, err error)
+	page   []T
+	index  int
+	cursor string
+	last   bool
+	item   T
+	err    error
+}
+
+func newIterator[T any](ctx context.Context, cursor string, fetch func(ctx context.Context, cursor string) ([]T, string, error)) *Iterator[T] {
+	return &Iterator[T]{
+		ctx:    ctx,
+		fetch:  fetch,
+		cursor: cursor,
+	}
+}
+
+// Next переходит к следующему элементу, при необходимости запрашивая следующую страницу.
+// Возвращает false, когда элементы закончились или произошла ошибка.
+func (it *Iterator[T]) Next() bool {
+	for it.index >= len(it.page) {
+		if it.last || it.err != nil {
+			return false
+		}
+
+		page, next, err := it.fetch(it.ctx, it.cursor)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		if next != "" && next == it.cursor {
+			it.err = fmt.Errorf("softline: pagination cursor %q did not advance", next)
+			return false
+		}
+
+		it.page, it.index = page, 0
+		it.cursor = next
+		it.last = next == ""
+	}
+
+	it.item = it.page[it.index]
+	it.index++
+	return true
+}
+
+// Item возвращает текущий элемент.
+func (it *Iterator[T]) Item() T {
+	return it.item
+}
+
+// Err возвращает ошибку, остановившую обход.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// IterateOrders обходит все заказы по фильтру начиная с filter.Cursor; filter.Limit задаёт размер страницы.
+func (s *Service) IterateOrders(ctx context.Context, filter OrderFilter, opts ...RequestOption) *Iterator[PaymentResp] {
+	return newIterator(ctx, filter.Cursor, func(ctx context.Context, cursor string) ([]PaymentResp, string, error) {
+		filter.Cursor = cursor
+		_, response, err := s.SearchOrders(ctx, filter, "", opts...)
+		if err != nil {
+			return nil, "", err
+		}
+		return response.Orders, response.NextCursor, nil
+	})
+}
+
+func (s *Service) IterateDisputes(ctx context.Context, request ListDisputesReq, opts ...RequestOption) *Iterator[Dispute] {
+	return newIterator(ctx, request.Cursor, func(ctx context.Context, cursor string) ([]Dispute, string, error) {
+		request.Cursor = cursor
+		_, response, err := s.ListDisputes(ctx, request, "", opts...)
+		if err != nil {
+			return nil, "", err
+		}
+		return response.Disputes, response.NextCursor, nil
+	})
+}
+
+func (s *Service) IteratePaymentLinks(ctx context.Context, request ListPaymentLinksReq, opts ...RequestOption) *Iterator[PaymentLink] {
+	return newIterator(ctx, request.Cursor, func(ctx context.Context, cursor string) ([]PaymentLink, string, error) {
+		request.Cursor = cursor
+		_, response, err := s.ListPaymentLinks(ctx, request, "", opts...)
+		if err != nil {
+			return nil, "", err
+		}
+		return response.Links, response.NextCursor, nil
+	})
+}
@@ -0,0 +1,149 @@
+package softlinePayment
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const journalCompleteTimeout = 5 * time.Second
+
+type JournalState string
+
+const (
+	JournalPending   JournalState = "pending"
+	JournalCompleted JournalState = "completed"
+	JournalFailed    JournalState = "failed"
+)
+
+// JournalEntry — запись о намерении выполнить изменяющий запрос к SOM.
+type JournalEntry struct {
+	RequestID      string
+	Operation      string
+	Method         string
+	Path           string
+	IdempotencyKey string
+	PayloadHash    string // SHA-256 тела в hex; пусто для потокового тела
+	State          JournalState
+	StartedAt      time.Time
+	CompletedAt    time.Time
+	HTTPStatus     int
+	Err            string
+}
+
+// Journal — журнал упреждающей записи. Begin вызывается до отправки изменяющего запроса
+// (не GET), Complete — после получения окончательного ответа. Записи, оставшиеся в
+// состоянии JournalPending после перезапуска, — запросы, результат которых неизвестен:
+// их можно сверить через PostCheck или повторить с тем же IdempotencyKey.
+type Journal interface {
+	Begin(ctx context.Context, entry JournalEntry) error
+	Complete(ctx context.Context, entry JournalEntry) error
+	Pending(ctx context.Context) ([]JournalEntry, error)
+}
+
+// WithJournal подключает журнал изменяющих запросов. Если Begin завершился ошибкой,
+// запрос в SOM не отправляется.
+func WithJournal(journal Journal) Option {
+	return func(s *Service) {
+		s.journal = journal
+	}
+}
+
+// journalBegin записывает намерение и возвращает функцию фиксации результата.
+func (s *Service) journalBegin(ctx context.Context, inputs *SendParams, reqBody *requestBody) (complete func(err error), err error) {
+	complete = func(error) {}
+	if s.journal == nil || inputs.HttpMethod == http.MethodGet || !inputs.AuthNeed {
+		return complete, nil
+	}
+
+	entry := JournalEntry{
+		RequestID:      inputs.RequestID,
+		Operation:      inputs.Operation,
+		Method:         inputs.HttpMethod,
+		Path:           inputs.Path,
+		IdempotencyKey: inputs.IdempotencyKey,
+		State:          JournalPending,
+		StartedAt:      time.Now(),
+	}
+	if reqBody == nil || reqBody.stream == nil {
+		var buf []byte
+		if reqBody != nil {
+			buf = reqBody.buf
+		}
+		sum := sha256.Sum256(buf)
+		entry.PayloadHash = hex.EncodeToString(sum[:])
+	}
+
+	if err = s.journal.Begin(ctx, entry); err != nil {
+		return complete, fmt.Errorf("journal: %w", err)
+	}
+
+	return func(err error) {
+		entry.CompletedAt = time.Now()
+		entry.HTTPStatus = inputs.HttpCode
+		entry.State = JournalCompleted
+		if err != nil {
+			entry.State = JournalFailed
+			entry.Err = err.Error()
+		}
+
+		// результат фиксируем, даже если ctx вызова уже отменён
+		ctx, cancel := context.WithTimeout(context.Background(), journalCompleteTimeout)
+		defer cancel()
+		if err := s.journal.Complete(ctx, entry); err != nil {
+			s.logger.Errorf("can't complete journal entry %s: %v", entry.RequestID, err)
+		}
+	}, nil
+}
+
+// MemoryJournal — Journal в памяти процесса: подходит для тестов, но не переживает перезапуск.
+type MemoryJournal struct {
+	mu      sync.Mutex
+	entries map[string]JournalEntry
+}
+
+func NewMemoryJournal() *MemoryJournal {
+	return &MemoryJournal{
+		entries: make(map[string]JournalEntry),
+	}
+}
+
+func (m *MemoryJournal) Begin(_ context.Context, entry JournalEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[entry.RequestID] = entry
+	return nil
+}
+
+// Complete удаляет успешно завершённые записи и сохраняет неудачные для разбора.
+func (m *MemoryJournal) Complete(_ context.Context, entry JournalEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if entry.State == JournalCompleted {
+		delete(m.entries, entry.RequestID)
+		return nil
+	}
+	m.entries[entry.RequestID] = entry
+	return nil
+}
+
+func (m *MemoryJournal) Pending(_ context.Context) ([]JournalEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var pending []JournalEntry
+	for _, entry := range m.entries {
+		if entry.State == JournalPending {
+			pending = append(pending, entry)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].StartedAt.Before(pending[j].StartedAt) })
+	return pending, nil
+}
@@ -0,0 +1,57 @@
+package softlinePayment
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	paymentLinks      = "/v1/payment_link"
+	revokePaymentLink = "/v1/payment_link/%s/revoke"
+)
+
+// CreatePaymentLink создаёт ссылку на страницу оплаты SOM с ограниченным сроком действия.
+func (s *Service) CreatePaymentLink(ctx context.Context, data CreatePaymentLinkReq, token string, opts ...RequestOption) (respBody []byte, response *PaymentLink, err error) {
+	inputs := &SendParams{
+		Operation:      "create_payment_link",
+		Path:           paymentLinks,
+		HttpMethod:     http.MethodPost,
+		AuthNeed:       true,
+		Idempotent:     true,
+		IdempotencyKey: data.IdempotencyKey,
+	}
+	respBody, response, err = call[CreatePaymentLinkReq, PaymentLink](ctx, s, inputs, &data, token, opts)
+	response.IdempotencyKey = inputs.IdempotencyKey
+
+	return
+}
+
+func (s *Service) ListPaymentLinks(ctx context.Context, request ListPaymentLinksReq, token string, opts ...RequestOption) (respBody []byte, response *PaymentLinkList, err error) {
+	query := queryParams{}.
+		Set("status", string(request.Status)).
+		Set("cursor", request.Cursor).
+		SetInt("limit", request.Limit)
+
+	return call[struct{}, PaymentLinkList](ctx, s, &SendParams{
+		Operation:   "list_payment_links",
+		Path:        paymentLinks,
+		HttpMethod:  http.MethodGet,
+		AuthNeed:    true,
+		QueryParams: query,
+	}, nil, token, opts)
+}
+
+func (s *Service) RevokePaymentLink(ctx context.Context, linkID string, token string, opts ...RequestOption) (respBody []byte, response *PaymentLink, err error) {
+	path, err := endpointPath(revokePaymentLink, linkID)
+	if err != nil {
+		return nil, new(PaymentLink), err
+	}
+
+	return call[struct{}, PaymentLink](ctx, s, &SendParams{
+		Operation:  "revoke_payment_link",
+		Path:       path,
+		HttpMethod: http.MethodPost,
+		AuthNeed:   true,
+		Idempotent: true,
+	}, nil, token, opts)
+}
@@ -0,0 +1,193 @@
+package softlinePayment
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// Logger — интерфейс логгера SDK. По умолчанию логирование отключено.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debugf(string, ...interface{}) {}
+func (nopLogger) Infof(string, ...interface{})  {}
+func (nopLogger) Warnf(string, ...interface{})  {}
+func (nopLogger) Errorf(string, ...interface{}) {}
+
+// StdLogger пишет в *log.Logger сообщения не ниже заданного уровня.
+type StdLogger struct {
+	logger *log.Logger
+	level  LogLevel
+}
+
+func NewStdLogger(logger *log.Logger, level LogLevel) *StdLogger {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &StdLogger{
+		logger: logger,
+		level:  level,
+	}
+}
+
+func (l *StdLogger) Debugf(format string, args ...interface{}) {
+	l.logf(LevelDebug, "DEBUG", format, args)
+}
+func (l *StdLogger) Infof(format string, args ...interface{}) {
+	l.logf(LevelInfo, "INFO", format, args)
+}
+func (l *StdLogger) Warnf(format string, args ...interface{}) {
+	l.logf(LevelWarn, "WARN", format, args)
+}
+func (l *StdLogger) Errorf(format string, args ...interface{}) {
+	l.logf(LevelError, "ERROR", format, args)
+}
+
+func (l *StdLogger) logf(level LogLevel, prefix, format string, args []interface{}) {
+	if level < l.level {
+		return
+	}
+	l.logger.Printf("softline "+prefix+": "+format, args...)
+}
+
+// WithLogger задаёт логгер Service.
+func WithLogger(logger Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// WithDebugLogging включает подробный лог запросов и ответов на уровне Debug:
+// метод, путь, заголовки и тела. Токены, учётные данные, PAN и CVV маскируются.
+func WithDebugLogging(enabled bool) Option {
+	return func(s *Service) {
+		s.debugLogging = enabled
+	}
+}
+
+const redacted = "***"
+
+// параметры, значения которых нельзя писать в лог
+var sensitiveKeys = []string{
+	"token", "password", "pass", "secret", "authorization",
+	"card", "pan", "cvv", "cvc", "signature", "cookie",
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURL маскирует чувствительные query-параметры и userinfo в URL.
+func redactURL(u *url.URL) string {
+	clean := *u
+	if clean.User != nil {
+		clean.User = url.User(redacted)
+	}
+
+	query := clean.Query()
+	for key := range query {
+		if isSensitiveKey(key) {
+			query.Set(key, redacted)
+		}
+	}
+	clean.RawQuery = query.Encode()
+
+	return clean.String()
+}
+
+// последовательности из 13–19 цифр (допускаются пробелы и дефисы) считаем номером карты
+var panPattern = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+
+// redactHeaders возвращает заголовки в виде строки с замаскированными секретами.
+func redactHeaders(header http.Header) string {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for i, key := range keys {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		value := strings.Join(header[key], ",")
+		if isSensitiveKey(key) {
+			value = redacted
+		}
+		b.WriteString(key + ": " + value)
+	}
+	return b.String()
+}
+
+// redactBody маскирует секреты в теле запроса или ответа. JSON разбирается
+// целиком, остальное проверяется только на номера карт.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return panPattern.ReplaceAllString(string(body), redacted)
+	}
+
+	clean, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return redacted
+	}
+	return string(clean)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if isSensitiveKey(key) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	case string:
+		return panPattern.ReplaceAllString(v, redacted)
+	case json.Number:
+		if panPattern.MatchString(v.String()) {
+			return redacted
+		}
+	}
+	return value
+}
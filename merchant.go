@@ -0,0 +1,50 @@
+package softlinePayment
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+var ErrUnknownMerchant = errors.New("softline: unknown merchant")
+
+// MerchantCredentials — учётные данные отдельного юрлица/мерчанта.
+type MerchantCredentials struct {
+	Login string
+	Pass  string
+}
+
+// ForMerchant возвращает Service, работающий от имени мерчанта из Config.Merchants.
+// Он делит с родителем HTTP-клиент, лимитер и прочую инфраструктуру, но имеет свой кэш токена.
+// После UpdateConfig родителя прежние экземпляры сохраняют старые настройки, поэтому
+// ForMerchant лучше вызывать на каждую операцию, а не запоминать результат.
+func (s *Service) ForMerchant(merchantID string) (*Service, error) {
+	merchant, ok := s.current().merchants[merchantID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownMerchant, merchantID)
+	}
+	return merchant, nil
+}
+
+// newMerchants создаёт сервисы мерчантов для конфига родителя.
+func (s *Service) newMerchants(config *Config) map[string]*Service {
+	if len(config.Merchants) == 0 {
+		return nil
+	}
+
+	merchants := make(map[string]*Service, len(config.Merchants))
+	for id, credentials := range config.Merchants {
+		cfg := *config
+		cfg.Login = credentials.Login
+		cfg.Pass = credentials.Pass
+		cfg.Merchants = nil
+		cfg.Secrets = nil
+
+		merchant := *s
+		merchant.live = new(atomic.Pointer[liveConfig])
+		merchant.live.Store(merchant.newLiveConfig(&cfg))
+
+		merchants[id] = &merchant
+	}
+	return merchants
+}
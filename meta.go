@@ -0,0 +1,55 @@
+package softlinePayment
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// ResponseMeta — метаданные HTTP-ответа SOM. Встраивается во все структуры ответов.
+type ResponseMeta struct {
+	HTTPStatus         int
+	RequestID          string
+	Date               string
+	RateLimitLimit     int
+	RateLimitRemaining int
+	RateLimitReset     int
+	Header             http.Header
+	Environment        Environment
+	Cached             bool // ответ взят из кэша, см. WithResponseCache
+}
+
+func (m *ResponseMeta) setResponseMeta(meta ResponseMeta) {
+	*m = meta
+}
+
+// metaReceiver реализуют ответы со встроенным ResponseMeta.
+type metaReceiver interface {
+	setResponseMeta(meta ResponseMeta)
+}
+
+func newResponseMeta(resp *http.Response) ResponseMeta {
+	header := resp.Header.Clone()
+
+	requestID := header.Get(requestIDHeader)
+	if requestID == "" {
+		requestID = header.Get("X-Correlation-ID")
+	}
+
+	return ResponseMeta{
+		HTTPStatus:         resp.StatusCode,
+		RequestID:          requestID,
+		Date:               header.Get("Date"),
+		RateLimitLimit:     headerInt(header, "X-RateLimit-Limit"),
+		RateLimitRemaining: headerInt(header, "X-RateLimit-Remaining"),
+		RateLimitReset:     headerInt(header, "X-RateLimit-Reset"),
+		Header:             header,
+	}
+}
+
+func headerInt(header http.Header, key string) int {
+	value, err := strconv.Atoi(header.Get(key))
+	if err != nil {
+		return 0
+	}
+	return value
+}
@@ -0,0 +1,64 @@
+package softlinePayment
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// ограничения SOM на метаданные заказа
+const (
+	MaxMetadataKeys        = 50
+	MaxMetadataKeyLength   = 40
+	MaxMetadataValueLength = 500
+)
+
+// Metadata — произвольные поля мерчанта, которые SOM сохраняет в заказе и возвращает
+// в ответах и колбэках. Ключи — латиница, цифры, "_" и "-".
+type Metadata map[string]string
+
+// Validate проверяет метаданные на ограничения SOM.
+func (m Metadata) Validate() error {
+	var errs []error
+
+	if len(m) > MaxMetadataKeys {
+		errs = append(errs, fmt.Errorf("metadata has %d keys, at most %d allowed", len(m), MaxMetadataKeys))
+	}
+
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		switch {
+		case key == "":
+			errs = append(errs, errors.New("metadata key must not be empty"))
+		case len(key) > MaxMetadataKeyLength:
+			errs = append(errs, fmt.Errorf("metadata key %q is longer than %d characters", key, MaxMetadataKeyLength))
+		case !validMetadataKey(key):
+			errs = append(errs, fmt.Errorf("metadata key %q must contain only latin letters, digits, '_' and '-'", key))
+		}
+		if utf8.RuneCountInString(m[key]) > MaxMetadataValueLength {
+			errs = append(errs, fmt.Errorf("metadata value of %q is longer than %d characters", key, MaxMetadataValueLength))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrValidation, errors.Join(errs...))
+	}
+	return nil
+}
+
+func validMetadataKey(key string) bool {
+	for _, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,47 @@
+package softlinePayment
+
+import (
+	"context"
+	"time"
+)
+
+// MetricsHook получает события клиента для экспорта метрик в собственную систему мониторинга.
+// Методы вызываются синхронно и должны быть быстрыми. Готовая реализация для Prometheus — пакет softlineprom.
+type MetricsHook interface {
+	// RequestDone — завершён вызов SOM; status 0 означает, что ответ не получен
+	RequestDone(operation string, status int, duration time.Duration, err error)
+	// Retry — повтор запроса, attempt — номер следующей попытки
+	Retry(operation string, attempt int)
+	// TokenRefreshed — получен новый токен или попытка авторизации завершилась ошибкой
+	TokenRefreshed(err error)
+}
+
+// WithMetricsHook подключает MetricsHook.
+func WithMetricsHook(hook MetricsHook) Option {
+	return func(s *Service) {
+		s.metrics = hook
+	}
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) RequestDone(string, int, time.Duration, error) {}
+func (nopMetrics) Retry(string, int)                             {}
+func (nopMetrics) TokenRefreshed(error)                          {}
+
+// CircuitState возвращает состояние предохранителя; без WithCircuitBreaker — всегда CircuitClosed.
+func (s *Service) CircuitState() CircuitState {
+	if s.breaker == nil {
+		return CircuitClosed
+	}
+	return s.breaker.State()
+}
+
+// refreshToken — функция авторизации для TokenManager с учётом метрик.
+func (s *Service) refreshToken(ctx context.Context) (*AuthResp, error) {
+	resp, err := s.Auth(ctx)
+	s.metrics.TokenRefreshed(err)
+	s.stats.tokenRefreshed(err)
+	s.events.Publish(LifecycleEvent{Type: EventTokenRefreshed, Operation: "auth", Err: err})
+	return resp, err
+}
@@ -6,15 +6,26 @@ import (
 )
 
 type SendParams struct {
-	HttpCode    int
-	Path        string
-	HttpMethod  string
-	Date        string
-	Token       string
-	AuthNeed    bool
-	Body        io.Reader
-	QueryParams map[string]string
-	Response    interface{}
+	HttpCode       int
+	Operation      string
+	RequestID      string
+	Path           string
+	HttpMethod     string
+	ContentType    string
+	Date           string
+	Token          string
+	AuthNeed       bool
+	Idempotent     bool
+	IdempotencyKey string
+	Body           io.Reader
+	Stream         bool
+	ContentLength  int64
+	QueryParams    map[string]string
+	Headers        map[string]string
+	Timeout        time.Duration
+	Retry          *RetryPolicy
+	NoCache        bool
+	Meta           ResponseMeta
 }
 
 type AuthReq struct {
@@ -23,32 +34,74 @@ type AuthReq struct {
 }
 
 type AuthResp struct {
+	ResponseMeta `json:"-"`
+
 	Token        string
 	RefreshToken string
 	Date         string
 }
 
 type CreatePaymentReq struct {
+	IdempotencyKey     string   `json:"-"`
 	Currency           string   `json:"currency"`
-	Amount             string   `json:"amount"`
+	Amount             Amount   `json:"amount"`
 	ReturnSuccessUrl   string   `json:"return_success_url"`
 	PaymentMethod      string   `json:"payment_method"`
 	RecurringIndicator bool     `json:"recurring_indicator"`
 	PaymentId          string   `json:"payment_id"`
 	PaymentDescription string   `json:"payment_description"`
 	Customer           Customer `json:"customer"`
+	CustomerId         string   `json:"customer_id,omitempty"`
+	Receipt            *Receipt `json:"receipt,omitempty"`
+	// оплата в рассрочку на странице SOM
+	Installments *InstallmentOptions `json:"installments,omitempty"`
+	// маркетплейс: субмерчант-получатель, комиссия площадки и распределение суммы
+	SubMerchantId string  `json:"sub_merchant_id,omitempty"`
+	Commission    *Amount `json:"commission_amount,omitempty"`
+	Splits        []Split `json:"splits,omitempty"`
+	// поля мерчанта, возвращаются в PaymentResp и колбэках
+	Metadata Metadata `json:"metadata,omitempty"`
+	// курс, зафиксированный GetFxQuote
+	FxQuoteId string `json:"fx_quote_id,omitempty"`
+	// оплата через Apple Pay или Google Pay без перехода на платёжную страницу
+	WalletToken *WalletToken `json:"wallet_token,omitempty"`
+	// адрес плательщика для AVS; в части регионов обязателен
+	BillingAddress *BillingAddress `json:"billing_address,omitempty"`
+}
+
+type InstallmentOptions struct {
+	Count       int    `json:"count"`
+	ProductCode string `json:"product_code,omitempty"`
 }
 
 type Customer struct {
 	Email     string `json:"email"`
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
+	Phone     string `json:"phone,omitempty"`
+}
+
+// BillingAddress — адрес плательщика, сверяется эмитентом при AVS.
+type BillingAddress struct {
+	Name       string `json:"name,omitempty"` // держатель карты, если отличается от покупателя
+	Country    string `json:"country"`        // ISO 3166-1 alpha-2
+	State      string `json:"state,omitempty"`
+	City       string `json:"city"`
+	PostalCode string `json:"postal_code"`
+	Line1      string `json:"line1,omitempty"`
+	Line2      string `json:"line2,omitempty"`
 }
 
 type CreatePaymentResp struct {
-	PaymentUrl string  `json:"payment_url,omitempty"`
-	OrderId    int     `json:"order_id"`
-	Errors     []Error `json:"errors,omitempty"`
+	ResponseMeta `json:"-"`
+
+	IdempotencyKey string            `json:"-"`
+	PaymentUrl     string            `json:"payment_url,omitempty"`
+	OrderId        int64             `json:"order_id"`
+	Status         PaymentStatus     `json:"status,omitempty"`
+	ThreeDS        *ThreeDSChallenge `json:"three_ds,omitempty"`
+	ErrorCode      string            `json:"payment_error_code,omitempty"`
+	Errors         []Error           `json:"errors,omitempty"`
 }
 
 type Error struct {
@@ -57,10 +110,11 @@ type Error struct {
 }
 
 type MakePaymentReq struct {
-	ParentOrderId      int    `json:"parent_order_id"`
+	IdempotencyKey     string `json:"-"`
+	ParentOrderId      int64  `json:"parent_order_id"`
 	PaymentId          string `json:"payment_id"`
 	Currency           string `json:"currency"`
-	Amount             string `json:"amount"`
+	Amount             Amount `json:"amount"`
 	PaymentDescription string `json:"payment_description"`
 }
 
@@ -72,22 +126,27 @@ type Signature struct {
 	PaymentMethod string
 	Currency      string
 	CustomerEmail string
+	Values        []string // если заданы, подписываются вместо полей выше, см. SignatureBuilder
 }
 
 type PaymentResp struct {
-	Signature      string    `json:"-"`
-	RespBody       []byte    `json:"-"`
-	Event          string    `json:"event"`
-	EventDate      time.Time `json:"event_date"`
-	OrderId        int       `json:"order_id"`
-	OrderName      string    `json:"order_name"`
-	Status         string    `json:"status"`
-	ExternalId     string    `json:"external_id"`
-	CreateDate     time.Time `json:"create_date"`
-	PayDate        string    `json:"pay_date"`
-	Currency       string    `json:"currency"`
-	Locale         string    `json:"locale"`
-	OrderDetailUrl string    `json:"order_detail_url"`
+	ResponseMeta `json:"-"`
+
+	Signature      string        `json:"-"`
+	RespBody       []byte        `json:"-"`
+	Event          string        `json:"event"`
+	EventDate      time.Time     `json:"event_date"`
+	OrderId        int64         `json:"order_id"`
+	OrderName      string        `json:"order_name"`
+	Status         PaymentStatus `json:"status"`
+	ExternalId     string        `json:"external_id"`
+	CreateDate     time.Time     `json:"create_date"`
+	PayDate        string        `json:"pay_date"`
+	Currency       string        `json:"currency"`
+	Locale         string        `json:"locale"`
+	OrderDetailUrl string        `json:"order_detail_url"`
+	Amount         Amount        `json:"amount"`
+	RefundedAmount Amount        `json:"refunded_amount"`
 	Customer       struct {
 		Email     string `json:"email"`
 		FirstName string `json:"first_name"`
@@ -109,11 +168,512 @@ type PaymentResp struct {
 		Reason string    `json:"reason"`
 		Date   time.Time `json:"date"`
 	} `json:"return"`
-	Errors []Error `json:"errors"`
+	Metadata Metadata `json:"metadata,omitempty"`
+	Errors   []Error  `json:"errors"`
 }
 
 type RefundReq struct {
-	OrderID     string `json:"-"`
-	Email       string `json:"email"`
-	Description string `json:"description"`
+	IdempotencyKey string             `json:"-"`
+	OrderID        string             `json:"-"`
+	Email          string             `json:"email"`
+	Description    string             `json:"description"`
+	ReasonCode     RefundReasonCode   `json:"reason_code,omitempty"`
+	Initiator      RefundInitiator    `json:"initiator,omitempty"`
+	Correction     *ReceiptCorrection `json:"correction,omitempty"`
+}
+
+type PartialRefundReq struct {
+	IdempotencyKey string             `json:"-"`
+	OrderID        string             `json:"-"`
+	Email          string             `json:"email"`
+	Description    string             `json:"description"`
+	Amount         Amount             `json:"amount"`
+	Currency       string             `json:"currency"`
+	Reason         string             `json:"reason,omitempty"`
+	ReasonCode     RefundReasonCode   `json:"reason_code,omitempty"`
+	Initiator      RefundInitiator    `json:"initiator,omitempty"`
+	Correction     *ReceiptCorrection `json:"correction,omitempty"`
+	Items          []RefundItem       `json:"items,omitempty"`
+}
+
+type RefundItem struct {
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+	Amount   Amount `json:"amount"`
+}
+
+type RefundResp struct {
+	ResponseMeta `json:"-"`
+
+	RefundId string  `json:"refund_id"`
+	OrderId  int64   `json:"order_id"`
+	Status   string  `json:"status"`
+	Amount   Amount  `json:"amount"`
+	Currency string  `json:"currency"`
+	Errors   []Error `json:"errors,omitempty"`
+}
+
+type CaptureReq struct {
+	OrderID string `json:"-"`
+	// сумма списания; nil — списать всю заблокированную сумму
+	Amount *Amount `json:"amount,omitempty"`
+}
+
+type CancelReq struct {
+	OrderID string `json:"-"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+type SubscriptionInterval string
+
+const (
+	IntervalDay   SubscriptionInterval = "day"
+	IntervalWeek  SubscriptionInterval = "week"
+	IntervalMonth SubscriptionInterval = "month"
+	IntervalYear  SubscriptionInterval = "year"
+)
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionActive   SubscriptionStatus = "active"
+	SubscriptionPaused   SubscriptionStatus = "paused"
+	SubscriptionCanceled SubscriptionStatus = "canceled"
+)
+
+type Schedule struct {
+	Interval      SubscriptionInterval `json:"interval"`
+	IntervalCount int                  `json:"interval_count"`
+	StartDate     time.Time            `json:"start_date"`
+	EndDate       *time.Time           `json:"end_date,omitempty"`
+	MaxRetries    int                  `json:"max_retries"`
+	RetryInterval int                  `json:"retry_interval_hours,omitempty"`
+}
+
+type CreateSubscriptionReq struct {
+	ParentOrderId      int64    `json:"parent_order_id"`
+	Currency           string   `json:"currency"`
+	Amount             Amount   `json:"amount"`
+	PaymentDescription string   `json:"payment_description"`
+	Schedule           Schedule `json:"schedule"`
+	CustomerId         string   `json:"customer_id,omitempty"`
+}
+
+type UpdateSubscriptionReq struct {
+	SubscriptionID string             `json:"-"`
+	Status         SubscriptionStatus `json:"status,omitempty"`
+	Amount         *Amount            `json:"amount,omitempty"`
+	Schedule       *Schedule          `json:"schedule,omitempty"`
+}
+
+type SubscriptionResp struct {
+	ResponseMeta `json:"-"`
+
+	SubscriptionId  string             `json:"subscription_id"`
+	ParentOrderId   int64              `json:"parent_order_id"`
+	Status          SubscriptionStatus `json:"status"`
+	Currency        string             `json:"currency"`
+	Amount          Amount             `json:"amount"`
+	Schedule        Schedule           `json:"schedule"`
+	NextPaymentDate *time.Time         `json:"next_payment_date,omitempty"`
+	Errors          []Error            `json:"errors,omitempty"`
+}
+
+// CustomerProfile — покупатель, сохранённый в SOM. Его CustomerId передаётся в платежах
+// и подписках вместо повторной отправки контактных данных.
+type CustomerProfile struct {
+	ResponseMeta `json:"-"`
+
+	CustomerId string    `json:"customer_id"`
+	ExternalId string    `json:"external_id,omitempty"`
+	Email      string    `json:"email"`
+	Phone      string    `json:"phone,omitempty"`
+	FirstName  string    `json:"first_name,omitempty"`
+	LastName   string    `json:"last_name,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	Errors     []Error   `json:"errors,omitempty"`
+}
+
+type CreateCustomerReq struct {
+	IdempotencyKey string `json:"-"`
+	ExternalId     string `json:"external_id,omitempty"`
+	Email          string `json:"email"`
+	Phone          string `json:"phone,omitempty"`
+	FirstName      string `json:"first_name,omitempty"`
+	LastName       string `json:"last_name,omitempty"`
+}
+
+// UpdateCustomerReq — частичное обновление: nil-поля не меняются.
+type UpdateCustomerReq struct {
+	CustomerID string  `json:"-"`
+	Email      *string `json:"email,omitempty"`
+	Phone      *string `json:"phone,omitempty"`
+	FirstName  *string `json:"first_name,omitempty"`
+	LastName   *string `json:"last_name,omitempty"`
+}
+
+type SaveCardReq struct {
+	CustomerID string `json:"-"`
+	// заказ, которым покупатель уже оплатил картой
+	OrderId int64 `json:"order_id"`
+}
+
+type CardToken struct {
+	ResponseMeta `json:"-"`
+
+	Token          string    `json:"token"`
+	CustomerId     string    `json:"customer_id"`
+	CardLast4      string    `json:"card_last_4"`
+	CardBrand      string    `json:"card_brand"`
+	ExpirationDate string    `json:"card_expiration_date"`
+	CreatedAt      time.Time `json:"created_at"`
+	Errors         []Error   `json:"errors,omitempty"`
+}
+
+type CardTokenList struct {
+	ResponseMeta `json:"-"`
+
+	Cards  []CardToken `json:"cards"`
+	Errors []Error     `json:"errors,omitempty"`
+}
+
+type PayWithCardReq struct {
+	IdempotencyKey     string `json:"-"`
+	CardToken          string `json:"card_token"`
+	CustomerId         string `json:"customer_id"`
+	Currency           string `json:"currency"`
+	Amount             Amount `json:"amount"`
+	PaymentId          string `json:"payment_id"`
+	PaymentDescription string `json:"payment_description"`
+}
+
+type PayoutStatus string
+
+const (
+	PayoutPending   PayoutStatus = "pending"
+	PayoutProcessed PayoutStatus = "processed"
+	PayoutFailed    PayoutStatus = "failed"
+	PayoutCanceled  PayoutStatus = "canceled"
+)
+
+type BeneficiaryType string
+
+const (
+	BeneficiaryIndividual BeneficiaryType = "individual"
+	BeneficiaryCompany    BeneficiaryType = "company"
+)
+
+type Beneficiary struct {
+	Type        BeneficiaryType `json:"type"`
+	FirstName   string          `json:"first_name,omitempty"`
+	LastName    string          `json:"last_name,omitempty"`
+	CompanyName string          `json:"company_name,omitempty"`
+	Email       string          `json:"email,omitempty"`
+	Phone       string          `json:"phone,omitempty"`
+	TaxId       string          `json:"tax_id,omitempty"`
+	Bank        BankDetails     `json:"bank"`
+}
+
+type BankDetails struct {
+	BankName             string `json:"bank_name"`
+	AccountNumber        string `json:"account_number,omitempty"`
+	IBAN                 string `json:"iban,omitempty"`
+	BIC                  string `json:"bic"`
+	CorrespondentAccount string `json:"correspondent_account,omitempty"`
+	Country              string `json:"country,omitempty"`
+}
+
+type CreatePayoutReq struct {
+	IdempotencyKey string      `json:"-"`
+	PayoutId       string      `json:"payout_id"`
+	Currency       string      `json:"currency"`
+	Amount         Amount      `json:"amount"`
+	Description    string      `json:"description"`
+	Beneficiary    Beneficiary `json:"beneficiary"`
+}
+
+type PayoutResp struct {
+	ResponseMeta `json:"-"`
+
+	IdempotencyKey string       `json:"-"`
+	PayoutId       string       `json:"payout_id"`
+	ExternalId     string       `json:"external_id"`
+	Status         PayoutStatus `json:"status"`
+	Currency       string       `json:"currency"`
+	Amount         Amount       `json:"amount"`
+	CreateDate     time.Time    `json:"create_date"`
+	ProcessDate    *time.Time   `json:"process_date,omitempty"`
+	FailureReason  string       `json:"failure_reason,omitempty"`
+	Errors         []Error      `json:"errors,omitempty"`
+}
+
+type RefundState string
+
+const (
+	RefundPending   RefundState = "pending"
+	RefundCompleted RefundState = "completed"
+	RefundFailed    RefundState = "failed"
+)
+
+type RefundStatusResp struct {
+	ResponseMeta `json:"-"`
+
+	RefundId       string      `json:"refund_id"`
+	OrderId        int64       `json:"order_id"`
+	State          RefundState `json:"state"`
+	Currency       string      `json:"currency"`
+	Amount         Amount      `json:"amount"`
+	AmountRefunded Amount      `json:"amount_refunded"`
+	CreateDate     time.Time   `json:"create_date"`
+	UpdateDate     *time.Time  `json:"update_date,omitempty"`
+	CompleteDate   *time.Time  `json:"complete_date,omitempty"`
+	FailureReason  string      `json:"failure_reason,omitempty"`
+	Errors         []Error     `json:"errors,omitempty"`
+}
+
+type DisputeStatus string
+
+const (
+	DisputeOpen        DisputeStatus = "open"
+	DisputeUnderReview DisputeStatus = "under_review"
+	DisputeWon         DisputeStatus = "won"
+	DisputeLost        DisputeStatus = "lost"
+	DisputeAccepted    DisputeStatus = "accepted"
+)
+
+type Dispute struct {
+	ResponseMeta `json:"-"`
+
+	DisputeId       string             `json:"dispute_id"`
+	OrderId         int64              `json:"order_id"`
+	Status          DisputeStatus      `json:"status"`
+	Reason          string             `json:"reason"`
+	ReasonCode      string             `json:"reason_code"`
+	Currency        string             `json:"currency"`
+	Amount          Amount             `json:"amount"`
+	CreateDate      time.Time          `json:"create_date"`
+	EvidenceDueDate *time.Time         `json:"evidence_due_date,omitempty"`
+	Evidence        []EvidenceDocument `json:"evidence,omitempty"`
+	Errors          []Error            `json:"errors,omitempty"`
+}
+
+type EvidenceDocument struct {
+	DocumentId  string    `json:"document_id"`
+	FileName    string    `json:"file_name"`
+	Description string    `json:"description,omitempty"`
+	UploadDate  time.Time `json:"upload_date"`
+}
+
+type ListDisputesReq struct {
+	Status DisputeStatus
+	From   time.Time
+	To     time.Time
+	Cursor string
+	Limit  int
+}
+
+type DisputeList struct {
+	ResponseMeta `json:"-"`
+
+	Disputes   []Dispute `json:"disputes"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+	Errors     []Error   `json:"errors,omitempty"`
+}
+
+type UploadEvidenceReq struct {
+	DisputeID   string
+	FileName    string
+	Description string
+	Content     io.Reader
+}
+
+type PaymentLinkStatus string
+
+const (
+	PaymentLinkActive  PaymentLinkStatus = "active"
+	PaymentLinkPaid    PaymentLinkStatus = "paid"
+	PaymentLinkExpired PaymentLinkStatus = "expired"
+	PaymentLinkRevoked PaymentLinkStatus = "revoked"
+)
+
+type CreatePaymentLinkReq struct {
+	IdempotencyKey     string    `json:"-"`
+	PaymentId          string    `json:"payment_id"`
+	Currency           string    `json:"currency"`
+	Amount             Amount    `json:"amount"`
+	PaymentDescription string    `json:"payment_description"`
+	ExpiresAt          time.Time `json:"expires_at"`
+	SingleUse          bool      `json:"single_use"`
+	Customer           *Customer `json:"customer,omitempty"`
+}
+
+type PaymentLink struct {
+	ResponseMeta `json:"-"`
+
+	IdempotencyKey string            `json:"-"`
+	LinkId         string            `json:"link_id"`
+	Url            string            `json:"url"`
+	Status         PaymentLinkStatus `json:"status"`
+	Currency       string            `json:"currency"`
+	Amount         Amount            `json:"amount"`
+	ExpiresAt      time.Time         `json:"expires_at"`
+	CreateDate     time.Time         `json:"create_date"`
+	OrderId        int64             `json:"order_id,omitempty"`
+	Errors         []Error           `json:"errors,omitempty"`
+}
+
+type ListPaymentLinksReq struct {
+	Status PaymentLinkStatus
+	Cursor string
+	Limit  int
+}
+
+type PaymentLinkList struct {
+	ResponseMeta `json:"-"`
+
+	Links      []PaymentLink `json:"links"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+	Errors     []Error       `json:"errors,omitempty"`
+}
+
+type InstallmentPlanStatus string
+
+const (
+	InstallmentPlanActive    InstallmentPlanStatus = "active"
+	InstallmentPlanCompleted InstallmentPlanStatus = "completed"
+	InstallmentPlanPaidOff   InstallmentPlanStatus = "paid_off"
+	InstallmentPlanDefaulted InstallmentPlanStatus = "defaulted"
+	InstallmentPlanCanceled  InstallmentPlanStatus = "canceled"
+)
+
+type InstallmentStatus string
+
+const (
+	InstallmentScheduled InstallmentStatus = "scheduled"
+	InstallmentPaid      InstallmentStatus = "paid"
+	InstallmentOverdue   InstallmentStatus = "overdue"
+	InstallmentFailed    InstallmentStatus = "failed"
+)
+
+type CreateInstallmentPlanReq struct {
+	IdempotencyKey     string               `json:"-"`
+	PaymentId          string               `json:"payment_id"`
+	CustomerId         string               `json:"customer_id,omitempty"`
+	Currency           string               `json:"currency"`
+	Amount             Amount               `json:"amount"`
+	Count              int                  `json:"count"`
+	Interval           SubscriptionInterval `json:"interval"`
+	FirstPaymentDate   *time.Time           `json:"first_payment_date,omitempty"`
+	ProductCode        string               `json:"product_code,omitempty"`
+	PaymentDescription string               `json:"payment_description"`
+	ReturnSuccessUrl   string               `json:"return_success_url,omitempty"`
+}
+
+// Installment — один платёж графика рассрочки.
+type Installment struct {
+	Number  int               `json:"number"`
+	DueDate time.Time         `json:"due_date"`
+	Amount  Amount            `json:"amount"`
+	Status  InstallmentStatus `json:"status"`
+	OrderId int64             `json:"order_id,omitempty"`
+	PaidAt  *time.Time        `json:"paid_at,omitempty"`
+}
+
+type InstallmentPlan struct {
+	ResponseMeta `json:"-"`
+
+	IdempotencyKey string                `json:"-"`
+	PlanId         string                `json:"plan_id"`
+	Status         InstallmentPlanStatus `json:"status"`
+	Currency       string                `json:"currency"`
+	Amount         Amount                `json:"amount"`
+	Outstanding    Amount                `json:"outstanding_amount"`
+	Count          int                   `json:"count"`
+	Schedule       []Installment         `json:"schedule"`
+	PaymentUrl     string                `json:"payment_url,omitempty"`
+	Errors         []Error               `json:"errors,omitempty"`
+}
+
+type PayOffInstallmentPlanReq struct {
+	IdempotencyKey string `json:"-"`
+	PlanID         string `json:"-"`
+}
+
+// OrderFilter — условия SearchOrders; пустые поля не ограничивают выборку.
+type OrderFilter struct {
+	From          time.Time
+	To            time.Time
+	Statuses      []PaymentStatus
+	CustomerEmail string
+	PaymentId     string
+	Cursor        string
+	Limit         int
+}
+
+type OrderList struct {
+	ResponseMeta `json:"-"`
+
+	Orders     []PaymentResp `json:"orders"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+	Errors     []Error       `json:"errors,omitempty"`
+}
+
+type FxQuoteReq struct {
+	From   string
+	To     string
+	Amount Amount
+}
+
+type FxQuote struct {
+	ResponseMeta `json:"-"`
+
+	QuoteId         string    `json:"quote_id"`
+	From            string    `json:"from"`
+	To              string    `json:"to"`
+	Rate            Amount    `json:"rate"`
+	Amount          Amount    `json:"amount"`
+	ConvertedAmount Amount    `json:"converted_amount"`
+	ExpiresAt       time.Time `json:"expires_at"`
+	Errors          []Error   `json:"errors,omitempty"`
+}
+
+type BINLookupReq struct {
+	BIN      string
+	Amount   Amount
+	Currency string
+}
+
+// PaymentMethodInfo — способ оплаты, доступный для транзакции.
+type PaymentMethodInfo struct {
+	Method    string  `json:"payment_method"`
+	Name      string  `json:"name"`
+	MinAmount *Amount `json:"min_amount,omitempty"`
+	MaxAmount *Amount `json:"max_amount,omitempty"`
+	Fee       *Amount `json:"fee,omitempty"`
+}
+
+type PaymentMethodsReq struct {
+	Amount   Amount
+	Currency string
+	Country  string // ISO 3166-1 alpha-2 страны покупателя; пусто — без учёта страны
+}
+
+type PaymentMethodList struct {
+	ResponseMeta `json:"-"`
+
+	PaymentMethods []PaymentMethodInfo `json:"payment_methods"`
+	Errors         []Error             `json:"errors,omitempty"`
+}
+
+type BINInfo struct {
+	ResponseMeta `json:"-"`
+
+	BIN            string              `json:"bin"`
+	CardBrand      string              `json:"card_brand"`
+	CardType       string              `json:"card_type"`
+	CardLevel      string              `json:"card_level,omitempty"`
+	IssuerName     string              `json:"issuer_name"`
+	IssuerCountry  string              `json:"issuer_country"`
+	PaymentMethods []PaymentMethodInfo `json:"payment_methods"`
+	Errors         []Error             `json:"errors,omitempty"`
 }
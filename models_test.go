@@ -0,0 +1,92 @@
+package softlinePayment
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// 2^53 + 1 — первое целое, которое теряется при разборе через float64.
+const largeOrderID int64 = 9007199254740993
+
+func TestOrderIDRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		field string
+		value interface{}
+		id    func(value interface{}) int64
+	}{
+		{
+			name:  "CreatePaymentResp",
+			field: "order_id",
+			value: new(CreatePaymentResp),
+			id:    func(value interface{}) int64 { return value.(*CreatePaymentResp).OrderId },
+		},
+		{
+			name:  "PaymentResp",
+			field: "order_id",
+			value: new(PaymentResp),
+			id:    func(value interface{}) int64 { return value.(*PaymentResp).OrderId },
+		},
+		{
+			name:  "RefundResp",
+			field: "order_id",
+			value: new(RefundResp),
+			id:    func(value interface{}) int64 { return value.(*RefundResp).OrderId },
+		},
+		{
+			name:  "RefundStatusResp",
+			field: "order_id",
+			value: new(RefundStatusResp),
+			id:    func(value interface{}) int64 { return value.(*RefundStatusResp).OrderId },
+		},
+		{
+			name:  "MakePaymentReq",
+			field: "parent_order_id",
+			value: new(MakePaymentReq),
+			id:    func(value interface{}) int64 { return value.(*MakePaymentReq).ParentOrderId },
+		},
+		{
+			name:  "SubscriptionResp",
+			field: "parent_order_id",
+			value: new(SubscriptionResp),
+			id:    func(value interface{}) int64 { return value.(*SubscriptionResp).ParentOrderId },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"` + tt.field + `":9007199254740993}`
+			if err := json.Unmarshal([]byte(body), tt.value); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if got := tt.id(tt.value); got != largeOrderID {
+				t.Fatalf("decoded %s = %d, want %d", tt.field, got, largeOrderID)
+			}
+
+			encoded, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if want := `"` + tt.field + `":9007199254740993`; !strings.Contains(string(encoded), want) {
+				t.Fatalf("encoded %s, want it to contain %s", encoded, want)
+			}
+		})
+	}
+}
+
+func TestOrderIDDecodeResponse(t *testing.T) {
+	body := []byte(`{"event":"payment.succeeded","order_id":9007199254740993,"status":"paid"}`)
+
+	for _, mode := range []DecodeMode{DecodeLenient, DecodeStrict} {
+		s := &Service{decodeMode: mode}
+
+		response := new(PaymentResp)
+		if err := s.decodeResponse("post_check", body, response); err != nil {
+			t.Fatalf("mode %d: decode: %v", mode, err)
+		}
+		if response.OrderId != largeOrderID {
+			t.Fatalf("mode %d: order_id = %d, want %d", mode, response.OrderId, largeOrderID)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package softlinePayment
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const maxAmountScale = 8
+
+var ErrInvalidAmount = errors.New("softline: invalid amount")
+
+// Amount — десятичная сумма без потерь точности: Units / 10^Scale.
+// В JSON сериализуется строкой, например "100.50".
+type Amount struct {
+	units int64
+	scale int
+}
+
+// NewAmount создаёт сумму из минорных единиц: NewAmount(10050, 2) == 100.50.
+// Паникует, если scale вне диапазона 0..8: точность задаётся в коде, как шаблон в MustParseAmount.
+func NewAmount(units int64, scale int) Amount {
+	if scale < 0 || scale > maxAmountScale {
+		panic(fmt.Errorf("%w: unsupported scale %d", ErrInvalidAmount, scale))
+	}
+	return Amount{units: units, scale: scale}
+}
+
+// ParseAmount разбирает десятичную строку вида "100", "100.5", "-0.01".
+func ParseAmount(s string) (Amount, error) {
+	raw := s
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	intPart, fracPart, hasDot := strings.Cut(s, ".")
+	if intPart == "" || (hasDot && fracPart == "") || len(fracPart) > maxAmountScale {
+		return Amount{}, fmt.Errorf("%w: %q", ErrInvalidAmount, raw)
+	}
+	for _, r := range intPart + fracPart {
+		if r < '0' || r > '9' {
+			return Amount{}, fmt.Errorf("%w: %q", ErrInvalidAmount, raw)
+		}
+	}
+
+	digits := intPart + fracPart
+	if negative {
+		digits = "-" + digits
+	}
+	units, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Amount{}, fmt.Errorf("%w: %q", ErrInvalidAmount, raw)
+	}
+
+	return Amount{units: units, scale: len(fracPart)}, nil
+}
+
+func MustParseAmount(s string) Amount {
+	a, err := ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func (a Amount) Scale() int {
+	return a.scale
+}
+
+func (a Amount) IsZero() bool {
+	return a.units == 0
+}
+
+func (a Amount) IsNegative() bool {
+	return a.units < 0
+}
+
+// Minor возвращает сумму в минорных единицах с заданной точностью.
+// Ошибка возвращается, если при этом теряются значащие цифры.
+func (a Amount) Minor(scale int) (int64, error) {
+	b, err := a.rescale(scale)
+	if err != nil {
+		return 0, err
+	}
+	return b.units, nil
+}
+
+// Rescale приводит сумму к заданному числу знаков после запятой без округления.
+func (a Amount) Rescale(scale int) (Amount, error) {
+	return a.rescale(scale)
+}
+
+func (a Amount) rescale(scale int) (Amount, error) {
+	if scale < 0 || scale > maxAmountScale {
+		return Amount{}, fmt.Errorf("%w: unsupported scale %d", ErrInvalidAmount, scale)
+	}
+
+	units := a.units
+	for s := a.scale; s < scale; s++ {
+		if units > math.MaxInt64/10 || units < math.MinInt64/10 {
+			return Amount{}, fmt.Errorf("%w: overflow", ErrInvalidAmount)
+		}
+		units *= 10
+	}
+	for s := a.scale; s > scale; s-- {
+		if units%10 != 0 {
+			return Amount{}, fmt.Errorf("%w: %s has more than %d decimal places", ErrInvalidAmount, a, scale)
+		}
+		units /= 10
+	}
+
+	return Amount{units: units, scale: scale}, nil
+}
+
+func (a Amount) align(b Amount) (Amount, Amount, error) {
+	scale := a.scale
+	if b.scale > scale {
+		scale = b.scale
+	}
+	x, err := a.rescale(scale)
+	if err != nil {
+		return Amount{}, Amount{}, err
+	}
+	y, err := b.rescale(scale)
+	if err != nil {
+		return Amount{}, Amount{}, err
+	}
+	return x, y, nil
+}
+
+func (a Amount) Add(b Amount) (Amount, error) {
+	x, y, err := a.align(b)
+	if err != nil {
+		return Amount{}, err
+	}
+	sum := x.units + y.units
+	if (y.units > 0 && sum < x.units) || (y.units < 0 && sum > x.units) {
+		return Amount{}, fmt.Errorf("%w: overflow", ErrInvalidAmount)
+	}
+	return Amount{units: sum, scale: x.scale}, nil
+}
+
+func (a Amount) Sub(b Amount) (Amount, error) {
+	return a.Add(Amount{units: -b.units, scale: b.scale})
+}
+
+// Cmp возвращает -1, 0 или 1.
+func (a Amount) Cmp(b Amount) int {
+	x, y, err := a.align(b)
+	if err != nil {
+		// выравнивание падает только на переполнении: сравниваем через float как запасной вариант
+		fa, fb := a.Float64(), b.Float64()
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+	switch {
+	case x.units < y.units:
+		return -1
+	case x.units > y.units:
+		return 1
+	}
+	return 0
+}
+
+// Float64 — только для отображения, не для расчётов.
+func (a Amount) Float64() float64 {
+	return float64(a.units) / math.Pow10(a.scale)
+}
+
+func (a Amount) String() string {
+	// модуль через uint64, чтобы не переполниться на math.MinInt64
+	units := uint64(a.units)
+	sign := ""
+	if a.units < 0 {
+		sign = "-"
+		units = -units
+	}
+
+	digits := strconv.FormatUint(units, 10)
+	if a.scale <= 0 {
+		return sign + digits
+	}
+	if len(digits) <= a.scale {
+		digits = strings.Repeat("0", a.scale-len(digits)+1) + digits
+	}
+	point := len(digits) - a.scale
+
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON принимает сумму как строкой, так и числом.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		if s == "" {
+			*a = Amount{}
+			return nil
+		}
+	}
+
+	parsed, err := ParseAmount(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+
+	return nil
+}
+
+// Money — сумма вместе с кодом валюты.
+type Money struct {
+	Amount   Amount
+	Currency string
+}
+
+func (m Money) String() string {
+	return m.Amount.String() + " " + m.Currency
+}
@@ -0,0 +1,66 @@
+package softlinePayment
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestAmountString(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		want   string
+	}{
+		{NewAmount(10050, 2), "100.50"},
+		{NewAmount(5, 2), "0.05"},
+		{NewAmount(-5, 2), "-0.05"},
+		{NewAmount(-10050, 2), "-100.50"},
+		{NewAmount(0, 0), "0"},
+		{NewAmount(0, 2), "0.00"},
+		{NewAmount(42, 0), "42"},
+		{NewAmount(1, 8), "0.00000001"},
+		{NewAmount(123456789, 8), "1.23456789"},
+		{NewAmount(math.MaxInt64, 8), "92233720368.54775807"},
+		{NewAmount(math.MinInt64, 8), "-92233720368.54775808"},
+		{NewAmount(math.MinInt64, 0), "-9223372036854775808"},
+		{Amount{}, "0"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.amount.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestNewAmountRejectsScale(t *testing.T) {
+	for _, scale := range []int{-1, maxAmountScale + 1} {
+		func() {
+			defer func() {
+				err, _ := recover().(error)
+				if !errors.Is(err, ErrInvalidAmount) {
+					t.Errorf("NewAmount(1, %d) panic = %v, want %v", scale, err, ErrInvalidAmount)
+				}
+			}()
+			NewAmount(1, scale)
+		}()
+	}
+}
+
+func TestParseAmountRoundTrip(t *testing.T) {
+	for _, s := range []string{"0", "100", "100.5", "-0.01", "0.00000001", "92233720368.54775807", "-9223372036854775808"} {
+		amount, err := ParseAmount(s)
+		if err != nil {
+			t.Fatalf("ParseAmount(%q): %v", s, err)
+		}
+		if got := amount.String(); got != s {
+			t.Errorf("ParseAmount(%q).String() = %q", s, got)
+		}
+	}
+
+	for _, s := range []string{"", "-", ".5", "5.", "1.123456789", "1e3", "9223372036854775808"} {
+		if _, err := ParseAmount(s); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("ParseAmount(%q) = %v, want %v", s, err, ErrInvalidAmount)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package softlinePayment
+
+import "net/http"
+
+// HTTPClient — минимальный интерфейс HTTP-клиента, которым пользуется Service.
+// Ему удовлетворяет *http.Client.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Option настраивает Service при создании.
+type Option func(s *Service)
+
+// WithHTTPClient подменяет HTTP-клиент, через который уходят запросы в SOM.
+func WithHTTPClient(client HTTPClient) Option {
+	return func(s *Service) {
+		s.client = client
+	}
+}
+
+// WithRetryPolicy переопределяет Config.Retry.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(s *Service) {
+		s.retry = policy
+	}
+}
+
+// WithUserAgent задаёт заголовок User-Agent исходящих запросов вместо "softlinePayment-go/<Version>".
+// X-SDK-Version отправляется в любом случае.
+func WithUserAgent(userAgent string) Option {
+	return func(s *Service) {
+		s.userAgent = userAgent
+	}
+}
+
+// WithBaseURL переопределяет Config.URI.
+func WithBaseURL(baseURL string) Option {
+	return func(s *Service) {
+		s.baseURL = baseURL
+	}
+}
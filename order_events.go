@@ -0,0 +1,72 @@
+package softlinePayment
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+)
+
+const orderEvents = "/v1/order/%s/events"
+
+type OrderEventType string
+
+const (
+	OrderEventCreated         OrderEventType = "created"
+	OrderEventThreeDSStarted  OrderEventType = "3ds_started"
+	OrderEventThreeDSFinished OrderEventType = "3ds_finished"
+	OrderEventAuthorized      OrderEventType = "authorized"
+	OrderEventDeclined        OrderEventType = "declined"
+	OrderEventCaptured        OrderEventType = "captured"
+	OrderEventCanceled        OrderEventType = "canceled"
+	OrderEventRefunded        OrderEventType = "refunded"
+	OrderEventChargeback      OrderEventType = "chargeback"
+)
+
+// OrderEvent — запись истории заказа: смена статуса или попытка оплаты.
+type OrderEvent struct {
+	Type             OrderEventType `json:"type"`
+	EventDate        time.Time      `json:"event_date"`
+	Status           PaymentStatus  `json:"status,omitempty"`
+	Amount           Amount         `json:"amount"`
+	Currency         string         `json:"currency,omitempty"`
+	AttemptId        string         `json:"attempt_id,omitempty"`
+	ErrorCode        string         `json:"error_code,omitempty"`
+	ErrorDescription string         `json:"error_description,omitempty"`
+	Initiator        string         `json:"initiator,omitempty"`
+	Description      string         `json:"description,omitempty"`
+}
+
+// DeclineReason нормализует код ошибки отклонённой попытки.
+func (e OrderEvent) DeclineReason() DeclineReason {
+	return ParseDeclineReason(e.ErrorCode)
+}
+
+type OrderEvents struct {
+	ResponseMeta `json:"-"`
+
+	OrderId int64        `json:"order_id"`
+	Events  []OrderEvent `json:"events"`
+	Errors  []Error      `json:"errors,omitempty"`
+}
+
+// GetOrderEvents возвращает историю заказа в хронологическом порядке.
+func (s *Service) GetOrderEvents(ctx context.Context, orderID string, token string, opts ...RequestOption) (respBody []byte, response *OrderEvents, err error) {
+	path, err := endpointPath(orderEvents, orderID)
+	if err != nil {
+		return nil, new(OrderEvents), err
+	}
+
+	respBody, response, err = call[struct{}, OrderEvents](ctx, s, &SendParams{
+		Operation:  "get_order_events",
+		Path:       path,
+		HttpMethod: http.MethodGet,
+		AuthNeed:   true,
+	}, nil, token, opts)
+
+	// SOM не гарантирует порядок записей
+	sort.SliceStable(response.Events, func(i, j int) bool {
+		return response.Events[i].EventDate.Before(response.Events[j].EventDate)
+	})
+	return
+}
@@ -0,0 +1,35 @@
+package softlinePayment
+
+import (
+	"context"
+	"net/http"
+)
+
+const orders = "/v1/order"
+
+// SearchOrders ищет заказы по фильтру; следующая страница запрашивается с Cursor = NextCursor.
+func (s *Service) SearchOrders(ctx context.Context, filter OrderFilter, token string, opts ...RequestOption) (respBody []byte, response *OrderList, err error) {
+	return call[struct{}, OrderList](ctx, s, &SendParams{
+		Operation:   "search_orders",
+		Path:        orders,
+		HttpMethod:  http.MethodGet,
+		AuthNeed:    true,
+		QueryParams: filter.query(),
+	}, nil, token, opts)
+}
+
+func (f OrderFilter) query() map[string]string {
+	statuses := make([]string, 0, len(f.Statuses))
+	for _, status := range f.Statuses {
+		statuses = append(statuses, string(status))
+	}
+
+	return queryParams{}.
+		SetTime("date_from", f.From).
+		SetTime("date_to", f.To).
+		SetList("status", statuses).
+		Set("customer_email", f.CustomerEmail).
+		Set("payment_id", f.PaymentId).
+		Set("cursor", f.Cursor).
+		SetInt("limit", f.Limit)
+}
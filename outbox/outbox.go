@@ -0,0 +1,264 @@
+// Package outbox — персистентная очередь исходящих операций SOM (возвраты, рекуррентные списания).
+// Операция сначала сохраняется в Store, затем обработчик отправляет её с повторами,
+// что даёт доставку «хотя бы один раз» при перезапусках процесса. Ключ идемпотентности
+// назначается при постановке в очередь, поэтому повторная отправка не приводит к двойному списанию.
+package outbox
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	softline "github.com/dwnGnL/softlinePayment"
+)
+
+type Kind string
+
+const (
+	KindRefund          Kind = "refund"
+	KindPartialRefund   Kind = "partial_refund"
+	KindRecurringCharge Kind = "recurring_charge"
+)
+
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	// попытки исчерпаны или ошибка неисправима; операция остаётся в Store для разбора
+	StatusDead Status = "dead"
+)
+
+const (
+	defaultInterval     = 5 * time.Second
+	defaultLease        = time.Minute
+	defaultMaxAttempts  = 10
+	defaultBatchSize    = 50
+	defaultRetryBackoff = 10 * time.Second
+	defaultMaxBackoff   = time.Hour
+)
+
+// Operation — запись очереди.
+type Operation struct {
+	ID             string
+	Kind           Kind
+	OrderID        string
+	IdempotencyKey string
+	Payload        json.RawMessage
+	Status         Status
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      string
+	CreatedAt      time.Time
+}
+
+// Client — методы SOM, которые выполняет очередь. Ему удовлетворяет *softline.Service.
+type Client interface {
+	Refund(ctx context.Context, request softline.RefundReq, token string, opts ...softline.RequestOption) (*softline.PaymentResp, error)
+	RefundPartial(ctx context.Context, request softline.PartialRefundReq, token string, opts ...softline.RequestOption) ([]byte, *softline.RefundResp, error)
+	MakePayment(ctx context.Context, data softline.MakePaymentReq, token string, opts ...softline.RequestOption) ([]byte, *softline.CreatePaymentResp, error)
+}
+
+// Outbox ставит операции в очередь и выполняет их.
+type Outbox struct {
+	store  Store
+	client Client
+
+	// период опроса Store в Run
+	Interval time.Duration
+	// время, на которое операция закрепляется за обработчиком
+	Lease       time.Duration
+	MaxAttempts int
+	BatchSize   int
+	// задержка перед повтором удваивается с каждой попыткой, но не больше MaxBackoff
+	RetryBackoff time.Duration
+	MaxBackoff   time.Duration
+	// вызывается, когда операция переходит в StatusDead
+	OnDead func(op Operation, err error)
+	// ошибки хранилища в Run
+	OnError func(err error)
+}
+
+func New(store Store, client Client) *Outbox {
+	return &Outbox{
+		store:        store,
+		client:       client,
+		Interval:     defaultInterval,
+		Lease:        defaultLease,
+		MaxAttempts:  defaultMaxAttempts,
+		BatchSize:    defaultBatchSize,
+		RetryBackoff: defaultRetryBackoff,
+		MaxBackoff:   defaultMaxBackoff,
+	}
+}
+
+func (o *Outbox) EnqueueRefund(ctx context.Context, request softline.RefundReq) (string, error) {
+	return o.enqueue(ctx, KindRefund, request.OrderID, request.IdempotencyKey, request)
+}
+
+func (o *Outbox) EnqueuePartialRefund(ctx context.Context, request softline.PartialRefundReq) (string, error) {
+	return o.enqueue(ctx, KindPartialRefund, request.OrderID, request.IdempotencyKey, request)
+}
+
+func (o *Outbox) EnqueueRecurringCharge(ctx context.Context, data softline.MakePaymentReq) (string, error) {
+	return o.enqueue(ctx, KindRecurringCharge, "", data.IdempotencyKey, data)
+}
+
+func (o *Outbox) enqueue(ctx context.Context, kind Kind, orderID, idempotencyKey string, request interface{}) (string, error) {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("outbox: can't encode %s: %w", kind, err)
+	}
+
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+	if idempotencyKey == "" {
+		idempotencyKey = id
+	}
+
+	now := time.Now()
+	op := Operation{
+		ID:             id,
+		Kind:           kind,
+		OrderID:        orderID,
+		IdempotencyKey: idempotencyKey,
+		Payload:        payload,
+		Status:         StatusPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}
+	if err = o.store.Enqueue(ctx, op); err != nil {
+		return "", fmt.Errorf("outbox: can't enqueue %s: %w", kind, err)
+	}
+	return id, nil
+}
+
+// Run обрабатывает очередь каждые Interval, пока не отменён ctx.
+func (o *Outbox) Run(ctx context.Context) error {
+	ticker := time.NewTicker(o.Interval)
+	defer ticker.Stop()
+
+	for {
+		// ошибки хранилища не останавливают обработчик, пробуем на следующем тике
+		if _, err := o.ProcessDue(ctx); err != nil && ctx.Err() == nil && o.OnError != nil {
+			o.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue выполняет одну порцию готовых операций и возвращает число успешно выполненных.
+func (o *Outbox) ProcessDue(ctx context.Context) (int, error) {
+	ops, err := o.store.Claim(ctx, time.Now(), o.Lease, o.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("outbox: can't claim operations: %w", err)
+	}
+
+	var done int
+	var errs []error
+	for _, op := range ops {
+		if ctx.Err() != nil {
+			break
+		}
+
+		execErr := o.execute(ctx, op)
+		if execErr == nil {
+			if err := o.store.Delete(ctx, op.ID); err != nil {
+				errs = append(errs, fmt.Errorf("outbox: can't delete %s: %w", op.ID, err))
+				continue
+			}
+			done++
+			continue
+		}
+
+		if err := o.reschedule(ctx, op, execErr); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return done, errors.Join(errs...)
+}
+
+func (o *Outbox) reschedule(ctx context.Context, op Operation, execErr error) error {
+	op.Attempts++
+	op.LastError = execErr.Error()
+
+	if permanent(execErr) || op.Attempts >= o.MaxAttempts {
+		op.Status = StatusDead
+		if o.OnDead != nil {
+			o.OnDead(op, execErr)
+		}
+	} else {
+		op.NextAttemptAt = time.Now().Add(o.backoff(op.Attempts))
+	}
+
+	if err := o.store.Update(ctx, op); err != nil {
+		return fmt.Errorf("outbox: can't update %s: %w", op.ID, err)
+	}
+	return nil
+}
+
+func (o *Outbox) backoff(attempts int) time.Duration {
+	delay := o.RetryBackoff
+	for i := 1; i < attempts && (o.MaxBackoff <= 0 || delay < o.MaxBackoff); i++ {
+		delay *= 2
+	}
+	if o.MaxBackoff > 0 && delay > o.MaxBackoff {
+		delay = o.MaxBackoff
+	}
+	return delay
+}
+
+func (o *Outbox) execute(ctx context.Context, op Operation) error {
+	switch op.Kind {
+	case KindRefund:
+		var request softline.RefundReq
+		if err := json.Unmarshal(op.Payload, &request); err != nil {
+			return fmt.Errorf("%w: %w", errMalformed, err)
+		}
+		request.OrderID, request.IdempotencyKey = op.OrderID, op.IdempotencyKey
+		_, err := o.client.Refund(ctx, request, "")
+		return err
+	case KindPartialRefund:
+		var request softline.PartialRefundReq
+		if err := json.Unmarshal(op.Payload, &request); err != nil {
+			return fmt.Errorf("%w: %w", errMalformed, err)
+		}
+		request.OrderID, request.IdempotencyKey = op.OrderID, op.IdempotencyKey
+		_, _, err := o.client.RefundPartial(ctx, request, "")
+		return err
+	case KindRecurringCharge:
+		var data softline.MakePaymentReq
+		if err := json.Unmarshal(op.Payload, &data); err != nil {
+			return fmt.Errorf("%w: %w", errMalformed, err)
+		}
+		data.IdempotencyKey = op.IdempotencyKey
+		_, _, err := o.client.MakePayment(ctx, data, "")
+		return err
+	}
+	return fmt.Errorf("%w: unknown kind %q", errMalformed, op.Kind)
+}
+
+var errMalformed = errors.New("outbox: malformed operation")
+
+// permanent — ошибки, которые не исправятся повтором.
+func permanent(err error) bool {
+	return errors.Is(err, errMalformed) || softline.IsPermanent(err)
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("outbox: can't generate id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,267 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	softline "github.com/dwnGnL/softlinePayment"
+)
+
+// fakeClient возвращает ошибки из errs по очереди, затем успешные ответы,
+// и запоминает ключи идемпотентности отправленных запросов.
+type fakeClient struct {
+	errs     []error
+	keys     []string
+	refunds  []softline.RefundReq
+	partials []softline.PartialRefundReq
+	charges  []softline.MakePaymentReq
+}
+
+func (c *fakeClient) next(key string) error {
+	c.keys = append(c.keys, key)
+	if len(c.errs) == 0 {
+		return nil
+	}
+	err := c.errs[0]
+	c.errs = c.errs[1:]
+	return err
+}
+
+func (c *fakeClient) Refund(_ context.Context, request softline.RefundReq, _ string, _ ...softline.RequestOption) (*softline.PaymentResp, error) {
+	c.refunds = append(c.refunds, request)
+	if err := c.next(request.IdempotencyKey); err != nil {
+		return nil, err
+	}
+	return &softline.PaymentResp{Status: softline.StatusRefunded}, nil
+}
+
+func (c *fakeClient) RefundPartial(_ context.Context, request softline.PartialRefundReq, _ string, _ ...softline.RequestOption) ([]byte, *softline.RefundResp, error) {
+	c.partials = append(c.partials, request)
+	if err := c.next(request.IdempotencyKey); err != nil {
+		return nil, nil, err
+	}
+	return nil, &softline.RefundResp{RefundId: "r-1", Status: "success", Amount: request.Amount}, nil
+}
+
+func (c *fakeClient) MakePayment(_ context.Context, data softline.MakePaymentReq, _ string, _ ...softline.RequestOption) ([]byte, *softline.CreatePaymentResp, error) {
+	c.charges = append(c.charges, data)
+	if err := c.next(data.IdempotencyKey); err != nil {
+		return nil, nil, err
+	}
+	return nil, &softline.CreatePaymentResp{OrderId: 100, Status: softline.StatusPaid}, nil
+}
+
+// newOutbox возвращает очередь, которая повторяет операции без задержки.
+func newOutbox(client Client) (*Outbox, *MemoryStore) {
+	store := NewMemoryStore()
+	outbox := New(store, client)
+	outbox.RetryBackoff = 0
+	return outbox, store
+}
+
+func processDue(t *testing.T, outbox *Outbox) int {
+	t.Helper()
+
+	done, err := outbox.ProcessDue(context.Background())
+	if err != nil {
+		t.Fatalf("ProcessDue: %v", err)
+	}
+	return done
+}
+
+func TestProcessDueExecutesEachKind(t *testing.T) {
+	client := &fakeClient{}
+	outbox, store := newOutbox(client)
+	ctx := context.Background()
+
+	refundID, err := outbox.EnqueueRefund(ctx, softline.RefundReq{OrderID: "42", Email: "buyer@example.com"})
+	if err != nil {
+		t.Fatalf("EnqueueRefund: %v", err)
+	}
+	if _, err = outbox.EnqueuePartialRefund(ctx, softline.PartialRefundReq{OrderID: "43", IdempotencyKey: "partial:43", Amount: softline.MustParseAmount("10.00"), Currency: "RUB"}); err != nil {
+		t.Fatalf("EnqueuePartialRefund: %v", err)
+	}
+	if _, err = outbox.EnqueueRecurringCharge(ctx, softline.MakePaymentReq{ParentOrderId: 1, IdempotencyKey: "charge:1"}); err != nil {
+		t.Fatalf("EnqueueRecurringCharge: %v", err)
+	}
+
+	if done := processDue(t, outbox); done != 3 {
+		t.Fatalf("done = %d, want 3", done)
+	}
+
+	if len(client.refunds) != 1 || client.refunds[0].OrderID != "42" || client.refunds[0].Email != "buyer@example.com" {
+		t.Fatalf("refunds = %+v", client.refunds)
+	}
+	// без ключа в запросе ключом становится id операции
+	if client.refunds[0].IdempotencyKey != refundID {
+		t.Fatalf("refund key = %q, want operation id %q", client.refunds[0].IdempotencyKey, refundID)
+	}
+	if len(client.partials) != 1 || client.partials[0].OrderID != "43" || client.partials[0].IdempotencyKey != "partial:43" ||
+		client.partials[0].Amount.String() != "10.00" {
+		t.Fatalf("partial refunds = %+v", client.partials)
+	}
+	if len(client.charges) != 1 || client.charges[0].ParentOrderId != 1 || client.charges[0].IdempotencyKey != "charge:1" {
+		t.Fatalf("charges = %+v", client.charges)
+	}
+
+	if _, ok := store.ops.Get(refundID); ok {
+		t.Fatal("completed operation is still in store")
+	}
+	if done := processDue(t, outbox); done != 0 {
+		t.Fatalf("second ProcessDue done = %d, want 0", done)
+	}
+}
+
+func TestTransientErrorRetriesWithSameKey(t *testing.T) {
+	for name, transient := range map[string]error{
+		"server":       softline.ErrServer,
+		"unauthorized": softline.ErrUnauthorized,
+		"rate limit":   softline.ErrRateLimited,
+	} {
+		t.Run(name, func(t *testing.T) {
+			client := &fakeClient{errs: []error{transient, transient}}
+			outbox, store := newOutbox(client)
+
+			id, err := outbox.EnqueueRefund(context.Background(), softline.RefundReq{OrderID: "42"})
+			if err != nil {
+				t.Fatalf("EnqueueRefund: %v", err)
+			}
+
+			for i := 0; i < 2; i++ {
+				if done := processDue(t, outbox); done != 0 {
+					t.Fatalf("attempt %d: done = %d, want 0", i+1, done)
+				}
+				op, ok := store.ops.Get(id)
+				if !ok || op.Status != StatusPending || op.Attempts != i+1 || op.LastError == "" {
+					t.Fatalf("attempt %d: operation = %+v", i+1, op)
+				}
+			}
+			if done := processDue(t, outbox); done != 1 {
+				t.Fatalf("done = %d, want 1", done)
+			}
+
+			if len(client.keys) != 3 || client.keys[0] != id || client.keys[1] != id || client.keys[2] != id {
+				t.Fatalf("keys = %v, want %s on every attempt", client.keys, id)
+			}
+			if dead := store.Dead(); len(dead) != 0 {
+				t.Fatalf("dead = %+v", dead)
+			}
+		})
+	}
+}
+
+func TestPermanentErrorMovesToDead(t *testing.T) {
+	client := &fakeClient{errs: []error{softline.ErrValidation}}
+	outbox, store := newOutbox(client)
+
+	var deadErr error
+	outbox.OnDead = func(_ Operation, err error) { deadErr = err }
+
+	id, err := outbox.EnqueueRefund(context.Background(), softline.RefundReq{OrderID: "42"})
+	if err != nil {
+		t.Fatalf("EnqueueRefund: %v", err)
+	}
+	processDue(t, outbox)
+	processDue(t, outbox)
+
+	if len(client.keys) != 1 {
+		t.Fatalf("calls = %d, want 1", len(client.keys))
+	}
+	if !errors.Is(deadErr, softline.ErrValidation) {
+		t.Fatalf("OnDead err = %v, want ErrValidation", deadErr)
+	}
+	dead := store.Dead()
+	if len(dead) != 1 || dead[0].ID != id || dead[0].Attempts != 1 {
+		t.Fatalf("dead = %+v", dead)
+	}
+}
+
+func TestMaxAttemptsMovesToDead(t *testing.T) {
+	client := &fakeClient{errs: []error{softline.ErrServer, softline.ErrServer, softline.ErrServer}}
+	outbox, store := newOutbox(client)
+	outbox.MaxAttempts = 3
+
+	var deadCalls int
+	outbox.OnDead = func(Operation, error) { deadCalls++ }
+
+	if _, err := outbox.EnqueueRefund(context.Background(), softline.RefundReq{OrderID: "42"}); err != nil {
+		t.Fatalf("EnqueueRefund: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		processDue(t, outbox)
+	}
+
+	if len(client.keys) != 3 || deadCalls != 1 {
+		t.Fatalf("calls = %d, OnDead calls = %d; want 3 and 1", len(client.keys), deadCalls)
+	}
+	if dead := store.Dead(); len(dead) != 1 || dead[0].Attempts != 3 {
+		t.Fatalf("dead = %+v", dead)
+	}
+}
+
+func TestMalformedOperationMovesToDead(t *testing.T) {
+	client := &fakeClient{}
+	outbox, store := newOutbox(client)
+
+	ops := []Operation{
+		{ID: "unknown", Kind: "transfer", Status: StatusPending, Payload: json.RawMessage(`{}`)},
+		{ID: "broken", Kind: KindRefund, Status: StatusPending, Payload: json.RawMessage(`{"email":`)},
+	}
+	for _, op := range ops {
+		if err := store.Enqueue(context.Background(), op); err != nil {
+			t.Fatal(err)
+		}
+	}
+	processDue(t, outbox)
+
+	if len(client.keys) != 0 {
+		t.Fatalf("client called with %v", client.keys)
+	}
+	if dead := store.Dead(); len(dead) != 2 {
+		t.Fatalf("dead = %+v, want both operations", dead)
+	}
+}
+
+func TestRescheduleBackoff(t *testing.T) {
+	client := &fakeClient{errs: []error{softline.ErrServer}}
+	store := NewMemoryStore()
+	outbox := New(store, client)
+
+	id, err := outbox.EnqueueRefund(context.Background(), softline.RefundReq{OrderID: "42"})
+	if err != nil {
+		t.Fatalf("EnqueueRefund: %v", err)
+	}
+	before := time.Now()
+	processDue(t, outbox)
+
+	op, _ := store.ops.Get(id)
+	if wait := op.NextAttemptAt.Sub(before); wait < defaultRetryBackoff || wait > defaultRetryBackoff+time.Second {
+		t.Fatalf("next attempt in %s, want %s", wait, defaultRetryBackoff)
+	}
+	if done := processDue(t, outbox); done != 0 || len(client.keys) != 1 {
+		t.Fatalf("operation retried before backoff: done = %d, calls = %d", done, len(client.keys))
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	outbox := &Outbox{RetryBackoff: 10 * time.Second, MaxBackoff: time.Minute}
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{3, 40 * time.Second},
+		{4, time.Minute},
+		{50, time.Minute},
+	}
+	for _, tt := range tests {
+		if got := outbox.backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
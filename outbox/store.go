@@ -0,0 +1,59 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/dwnGnL/softlinePayment/internal/lease"
+)
+
+// Store хранит операции очереди. Для доставки между перезапусками процесса
+// реализация должна быть персистентной (таблица в БД и т.п.).
+type Store interface {
+	Enqueue(ctx context.Context, op Operation) error
+	// Claim атомарно выбирает до limit ожидающих операций с NextAttemptAt <= now
+	// и переносит их NextAttemptAt на now+lease, чтобы их не взял другой обработчик.
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Operation, error)
+	Update(ctx context.Context, op Operation) error
+	Delete(ctx context.Context, id string) error
+}
+
+// MemoryStore — Store в памяти процесса: для тестов и разработки, перезапуск не переживает.
+type MemoryStore struct {
+	ops *lease.Store[Operation]
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		ops: lease.NewStore(lease.Rules[Operation]{
+			ID:    func(op Operation) string { return op.ID },
+			DueAt: func(op Operation) time.Time { return op.NextAttemptAt },
+			Ready: func(op Operation, _ time.Time) bool { return op.Status == StatusPending },
+			Lease: func(op *Operation, until time.Time) { op.NextAttemptAt = until },
+		}),
+	}
+}
+
+func (m *MemoryStore) Enqueue(_ context.Context, op Operation) error {
+	m.ops.Put(op)
+	return nil
+}
+
+func (m *MemoryStore) Claim(_ context.Context, now time.Time, lease time.Duration, limit int) ([]Operation, error) {
+	return m.ops.Claim(now, lease, limit), nil
+}
+
+func (m *MemoryStore) Update(_ context.Context, op Operation) error {
+	m.ops.Put(op)
+	return nil
+}
+
+func (m *MemoryStore) Delete(_ context.Context, id string) error {
+	m.ops.Delete(id)
+	return nil
+}
+
+// Dead возвращает операции, исчерпавшие попытки.
+func (m *MemoryStore) Dead() []Operation {
+	return m.ops.Filter(func(op Operation) bool { return op.Status == StatusDead })
+}
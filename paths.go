@@ -0,0 +1,46 @@
+package softlinePayment
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Шаблоны путей SOM объявлены константами рядом с эндпоинтами ("/v1/order/%s/refund").
+// Путь собирается только через endpointPath, а к базовому URI присоединяется joinURL,
+// поэтому слэши и префиксы вроде /api в Config.URI не дают путей //v1/... или v1/... без слэша.
+
+// endpointPath подставляет параметры в шаблон пути, экранируя каждый как сегмент:
+// идентификатор со слэшем или ? не изменит маршрут. PathEscape не трогает "." и "..",
+// а JoinPath их схлопывает, поэтому такие и пустые параметры отклоняются.
+func endpointPath(template string, params ...string) (string, error) {
+	args := make([]any, len(params))
+	for i, param := range params {
+		switch param {
+		case "":
+			return "", fmt.Errorf("%w: path parameter is empty", ErrValidation)
+		case ".", "..":
+			return "", fmt.Errorf("%w: path parameter %q is not allowed", ErrValidation, param)
+		}
+		args[i] = url.PathEscape(param)
+	}
+	return fmt.Sprintf(template, args...), nil
+}
+
+// joinURL присоединяет путь эндпоинта к базовому URI с сохранением его пути и
+// добавляет параметры запроса к уже имеющимся в URI.
+func joinURL(base, path string, params map[string]string) (*url.URL, error) {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse URI from config: %w", err)
+	}
+
+	// path уже экранирован endpointPath
+	requestURL := baseURL.JoinPath(path)
+
+	query := requestURL.Query()
+	for key, value := range params {
+		query.Set(key, value)
+	}
+	requestURL.RawQuery = query.Encode()
+	return requestURL, nil
+}
@@ -0,0 +1,63 @@
+package softlinePayment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const paymentMethods = "/v1/payment_method"
+
+// GetAvailablePaymentMethods возвращает способы оплаты, которые SOM примет для суммы,
+// валюты и страны покупателя, чтобы checkout не показывал недоступные варианты.
+func (s *Service) GetAvailablePaymentMethods(ctx context.Context, request PaymentMethodsReq, token string, opts ...RequestOption) (respBody []byte, response *PaymentMethodList, err error) {
+	if err = request.validate(); err != nil {
+		return nil, new(PaymentMethodList), err
+	}
+
+	query := queryParams{}.
+		Set("amount", request.Amount.String()).
+		Set("currency", strings.ToUpper(request.Currency)).
+		Set("country", strings.ToUpper(request.Country))
+
+	return call[struct{}, PaymentMethodList](ctx, s, &SendParams{
+		Operation:   "get_payment_methods",
+		Path:        paymentMethods,
+		HttpMethod:  http.MethodGet,
+		AuthNeed:    true,
+		QueryParams: query,
+	}, nil, token, opts)
+}
+
+func (r PaymentMethodsReq) validate() error {
+	if err := ValidateAmount(r.Amount, r.Currency); err != nil {
+		return err
+	}
+	if r.Country != "" && !isCountryCode(r.Country) {
+		return fmt.Errorf("%w: country must be an ISO 3166-1 alpha-2 code, got %q", ErrValidation, r.Country)
+	}
+	return nil
+}
+
+func isCountryCode(country string) bool {
+	if len(country) != 2 {
+		return false
+	}
+	for _, r := range strings.ToUpper(country) {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Supports сообщает, доступен ли способ оплаты.
+func (l *PaymentMethodList) Supports(method string) bool {
+	for _, info := range l.PaymentMethods {
+		if info.Method == method {
+			return true
+		}
+	}
+	return false
+}
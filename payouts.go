@@ -0,0 +1,55 @@
+package softlinePayment
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	payouts      = "/v1/payout"
+	payout       = "/v1/payout/%s"
+	cancelPayout = "/v1/payout/%s/cancel"
+)
+
+func (s *Service) CreatePayout(ctx context.Context, data CreatePayoutReq, token string, opts ...RequestOption) (respBody []byte, response *PayoutResp, err error) {
+	inputs := &SendParams{
+		Operation:      "create_payout",
+		Path:           payouts,
+		HttpMethod:     http.MethodPost,
+		AuthNeed:       true,
+		Idempotent:     true,
+		IdempotencyKey: data.IdempotencyKey,
+	}
+	respBody, response, err = call[CreatePayoutReq, PayoutResp](ctx, s, inputs, &data, token, opts)
+	response.IdempotencyKey = inputs.IdempotencyKey
+
+	return
+}
+
+func (s *Service) GetPayout(ctx context.Context, payoutID string, token string, opts ...RequestOption) (respBody []byte, response *PayoutResp, err error) {
+	path, err := endpointPath(payout, payoutID)
+	if err != nil {
+		return nil, new(PayoutResp), err
+	}
+
+	return call[struct{}, PayoutResp](ctx, s, &SendParams{
+		Operation:  "get_payout",
+		Path:       path,
+		HttpMethod: http.MethodGet,
+		AuthNeed:   true,
+	}, nil, token, opts)
+}
+
+func (s *Service) CancelPayout(ctx context.Context, payoutID string, token string, opts ...RequestOption) (respBody []byte, response *PayoutResp, err error) {
+	path, err := endpointPath(cancelPayout, payoutID)
+	if err != nil {
+		return nil, new(PayoutResp), err
+	}
+
+	return call[struct{}, PayoutResp](ctx, s, &SendParams{
+		Operation:  "cancel_payout",
+		Path:       path,
+		HttpMethod: http.MethodPost,
+		AuthNeed:   true,
+	}, nil, token, opts)
+}
@@ -0,0 +1,14 @@
+package softlinePayment
+
+import (
+	"context"
+	"time"
+)
+
+// Ping проверяет доступность SOM и учётные данные, выполняя авторизацию без повторов
+// и без кэша токенов. Возвращает время ответа SOM; подходит для readiness-проб.
+func (s *Service) Ping(ctx context.Context) (latency time.Duration, err error) {
+	started := time.Now()
+	_, err = s.Auth(ctx, WithoutRetries())
+	return time.Since(started), err
+}
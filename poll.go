@@ -0,0 +1,79 @@
+package softlinePayment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PollOptions задаёт интервалы опроса статуса заказа.
+type PollOptions struct {
+	Interval    time.Duration
+	MaxInterval time.Duration
+	Multiplier  float64
+}
+
+func DefaultPollOptions() PollOptions {
+	return PollOptions{
+		Interval:    time.Second,
+		MaxInterval: 15 * time.Second,
+		Multiplier:  1.5,
+	}
+}
+
+func (o PollOptions) next(current time.Duration) time.Duration {
+	if o.Multiplier > 1 {
+		current = time.Duration(float64(current) * o.Multiplier)
+	}
+	if o.MaxInterval > 0 && current > o.MaxInterval {
+		current = o.MaxInterval
+	}
+	return current
+}
+
+var ErrUnexpectedStatus = errors.New("softline: order reached unexpected terminal status")
+
+// WaitForPaymentStatus опрашивает PostCheck, пока заказ не перейдёт в один из targetStatuses
+// или не истечёт ctx. Пустой targetStatuses означает любой окончательный статус.
+// Если заказ пришёл в другой окончательный статус, возвращается ErrUnexpectedStatus.
+// При истечении ctx возвращается последний полученный ответ.
+func (s *Service) WaitForPaymentStatus(ctx context.Context, orderID string, targetStatuses []PaymentStatus, opts PollOptions) (*PaymentResp, error) {
+	if opts.Interval <= 0 {
+		opts = DefaultPollOptions()
+	}
+
+	targets := make(map[PaymentStatus]struct{}, len(targetStatuses))
+	for _, status := range targetStatuses {
+		targets[status] = struct{}{}
+	}
+
+	var last *PaymentResp
+	interval := opts.Interval
+	for {
+		_, response, err := s.PostCheck(ctx, orderID, "", WithoutCache())
+		if err != nil && ctx.Err() == nil {
+			return last, err
+		}
+		if err == nil {
+			last = response
+			if response.Status.IsTerminal() {
+				s.events.Publish(LifecycleEvent{Type: EventPaymentCompleted, Operation: "post_check", Payment: response})
+			}
+			if _, ok := targets[response.Status]; ok {
+				return response, nil
+			}
+			if response.Status.IsTerminal() {
+				if len(targets) == 0 {
+					return response, nil
+				}
+				return response, fmt.Errorf("%w: order %s is %s", ErrUnexpectedStatus, orderID, response.Status)
+			}
+		}
+
+		if err = sleepCtx(ctx, interval); err != nil {
+			return last, fmt.Errorf("order %s did not reach %v: %w", orderID, targetStatuses, err)
+		}
+		interval = opts.next(interval)
+	}
+}
@@ -0,0 +1,36 @@
+package softlinePayment
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// queryParams собирает SendParams.QueryParams из типизированных значений, пропуская пустые.
+// Экранирование выполняет sendRequest при сборке URL.
+type queryParams map[string]string
+
+func (q queryParams) Set(key, value string) queryParams {
+	if value != "" {
+		q[key] = value
+	}
+	return q
+}
+
+func (q queryParams) SetList(key string, values []string) queryParams {
+	return q.Set(key, strings.Join(values, ","))
+}
+
+func (q queryParams) SetInt(key string, value int) queryParams {
+	if value > 0 {
+		q[key] = strconv.Itoa(value)
+	}
+	return q
+}
+
+func (q queryParams) SetTime(key string, value time.Time) queryParams {
+	if !value.IsZero() {
+		q[key] = value.Format(time.RFC3339)
+	}
+	return q
+}
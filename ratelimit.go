@@ -0,0 +1,68 @@
+package softlinePayment
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter ограничивает частоту запросов к SOM. Реализация может быть распределённой,
+// чтобы делить квоту между процессами.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// WithRateLimiter задаёт ограничитель частоты запросов вместо создаваемого по Config.
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(s *Service) {
+		s.limiter = limiter
+	}
+}
+
+// TokenBucketLimiter — локальный ограничитель по алгоритму token bucket.
+type TokenBucketLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func NewRateLimiter(rps float64, burst int) *TokenBucketLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucketLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait блокируется, пока не освободится слот, или до отмены ctx.
+func (l *TokenBucketLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	// резервируем слот сразу, даже если придётся ждать: очередь сохраняет порядок
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if err := sleepCtx(ctx, delay); err != nil {
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
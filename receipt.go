@@ -0,0 +1,90 @@
+package softlinePayment
+
+import (
+	"errors"
+	"fmt"
+)
+
+type VATRate string
+
+const (
+	VATNone VATRate = "none"
+	VAT0    VATRate = "vat0"
+	VAT10   VATRate = "vat10"
+	VAT20   VATRate = "vat20"
+	VAT110  VATRate = "vat110"
+	VAT120  VATRate = "vat120"
+)
+
+func (v VATRate) IsValid() bool {
+	switch v {
+	case VATNone, VAT0, VAT10, VAT20, VAT110, VAT120:
+		return true
+	}
+	return false
+}
+
+// Receipt — данные фискального чека, передаваемые вместе с платежом.
+type Receipt struct {
+	Customer  ReceiptCustomer `json:"customer"`
+	TaxSystem int             `json:"tax_system,omitempty"`
+	Items     []ReceiptItem   `json:"items"`
+}
+
+// ReceiptCustomer — контакт покупателя, на который ОФД отправит чек.
+type ReceiptCustomer struct {
+	Email string `json:"email,omitempty"`
+	Phone string `json:"phone,omitempty"`
+}
+
+type ReceiptItem struct {
+	Name           string  `json:"name"`
+	Quantity       Amount  `json:"quantity"`
+	Price          Amount  `json:"price"`
+	Amount         Amount  `json:"amount"`
+	VAT            VATRate `json:"vat"`
+	PaymentSubject string  `json:"payment_subject,omitempty"`
+	PaymentMode    string  `json:"payment_mode,omitempty"`
+}
+
+// Validate проверяет чек и то, что сумма позиций совпадает с суммой платежа.
+func (r *Receipt) Validate(total Amount) error {
+	var errs []error
+
+	if r.Customer.Email == "" && r.Customer.Phone == "" {
+		errs = append(errs, errors.New("receipt customer email or phone is required"))
+	}
+	if len(r.Items) == 0 {
+		errs = append(errs, errors.New("receipt must contain at least one item"))
+	}
+
+	var sum Amount
+	for i, item := range r.Items {
+		if item.Name == "" {
+			errs = append(errs, fmt.Errorf("receipt item %d: name is required", i))
+		}
+		if item.Quantity.Cmp(Amount{}) <= 0 {
+			errs = append(errs, fmt.Errorf("receipt item %d: quantity must be positive", i))
+		}
+		if item.Amount.IsNegative() || item.Price.IsNegative() {
+			errs = append(errs, fmt.Errorf("receipt item %d: price and amount must not be negative", i))
+		}
+		if !item.VAT.IsValid() {
+			errs = append(errs, fmt.Errorf("receipt item %d: unknown vat rate %q", i, item.VAT))
+		}
+
+		var err error
+		if sum, err = sum.Add(item.Amount); err != nil {
+			errs = append(errs, fmt.Errorf("receipt item %d: %w", i, err))
+		}
+	}
+
+	if len(r.Items) > 0 && sum.Cmp(total) != 0 {
+		errs = append(errs, fmt.Errorf("receipt items total %s does not match payment amount %s", sum, total))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrValidation, errors.Join(errs...))
+	}
+	return nil
+}
@@ -0,0 +1,322 @@
+// Package recurring выполняет рекуррентные списания (MakePayment) по расписанию cron
+// в заданных временных границах. Шаблоны и время следующего запуска хранятся в Store,
+// поэтому после перезапуска процесса пропущенные списания выполняются (catch-up),
+// а ключ идемпотентности каждого запуска строится из шаблона и запланированного времени,
+// что исключает двойное списание при повторной обработке.
+package recurring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	softline "github.com/dwnGnL/softlinePayment"
+)
+
+var ErrDeclined = errors.New("recurring: payment declined")
+
+type State string
+
+const (
+	StateActive    State = "active"
+	StateCompleted State = "completed"
+)
+
+// CatchUp — что делать с запусками, пропущенными, пока планировщик не работал.
+type CatchUp string
+
+const (
+	// CatchUpLatest выполняет одно списание за последний пропущенный запуск, остальные передаёт в OnSkipped
+	CatchUpLatest CatchUp = "latest"
+	// CatchUpAll выполняет списание за каждый пропущенный запуск
+	CatchUpAll CatchUp = "all"
+)
+
+const (
+	defaultInterval   = time.Minute
+	defaultLease      = 5 * time.Minute
+	defaultBatchSize  = 50
+	defaultMaxCatchUp = 100
+)
+
+// Template — рекуррентное списание по расписанию.
+type Template struct {
+	ID       string
+	Request  softline.MakePaymentReq
+	Schedule string // выражение cron, см. ParseCron
+	Location string // часовой пояс расписания по IANA; пусто — UTC
+	StartAt  time.Time
+	EndAt    time.Time // нулевое — без ограничения
+
+	State       State
+	NextRunAt   time.Time
+	LeaseUntil  time.Time
+	Runs        int
+	LastRunAt   time.Time
+	LastOrderId int64
+	LastStatus  softline.PaymentStatus
+	LastError   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Run — одно запланированное списание.
+type Run struct {
+	TemplateID     string
+	ScheduledAt    time.Time
+	IdempotencyKey string
+	Response       *softline.CreatePaymentResp
+}
+
+// Client — метод SOM, которым выполняются списания. Ему удовлетворяет *softline.Service.
+type Client interface {
+	MakePayment(ctx context.Context, data softline.MakePaymentReq, token string, opts ...softline.RequestOption) ([]byte, *softline.CreatePaymentResp, error)
+}
+
+// Scheduler запускает списания по расписанию шаблонов из Store.
+type Scheduler struct {
+	store  Store
+	client Client
+
+	Interval   time.Duration
+	Lease      time.Duration
+	BatchSize  int
+	CatchUp    CatchUp
+	MaxCatchUp int // предел списаний за одну обработку шаблона (CatchUpAll) и запусков в OnSkipped
+
+	// списание выполнено
+	OnCharged func(template Template, run Run)
+	// списание отклонено или ошибка неисправима; повтор можно передать в dunning
+	OnFailed func(template Template, run Run, err error)
+	// пропущенные запуски, за которые списание не выполнялось (CatchUpLatest); первые MaxCatchUp
+	OnSkipped func(template Template, missed []time.Time)
+	// расписание исчерпано или вышло за EndAt; шаблон удалён из Store
+	OnCompleted func(template Template)
+	// ошибки хранилища и временные ошибки SOM в Run; запуск будет повторён
+	OnError func(err error)
+}
+
+func New(store Store, client Client) *Scheduler {
+	return &Scheduler{
+		store:      store,
+		client:     client,
+		Interval:   defaultInterval,
+		Lease:      defaultLease,
+		BatchSize:  defaultBatchSize,
+		CatchUp:    CatchUpLatest,
+		MaxCatchUp: defaultMaxCatchUp,
+	}
+}
+
+// Schedule проверяет шаблон и сохраняет его с первым запуском не раньше StartAt и текущего времени.
+func (s *Scheduler) Schedule(ctx context.Context, template Template) (Template, error) {
+	if template.ID == "" {
+		return template, fmt.Errorf("%w: template ID is required", softline.ErrValidation)
+	}
+	schedule, location, err := template.parse()
+	if err != nil {
+		return template, err
+	}
+
+	now := time.Now()
+	start := template.StartAt
+	if start.Before(now) {
+		start = now
+	}
+	template.NextRunAt = schedule.Next(start.In(location).Add(-time.Nanosecond))
+	if template.NextRunAt.IsZero() || (!template.EndAt.IsZero() && template.NextRunAt.After(template.EndAt)) {
+		return template, fmt.Errorf("%w: schedule %q has no runs before %s", softline.ErrValidation, template.Schedule, template.EndAt)
+	}
+
+	template.State = StateActive
+	template.LeaseUntil = time.Time{}
+	template.CreatedAt = now
+	template.UpdatedAt = now
+	if err = s.store.Save(ctx, template); err != nil {
+		return template, fmt.Errorf("recurring: can't save %s: %w", template.ID, err)
+	}
+	return template, nil
+}
+
+// Cancel удаляет шаблон; уже начатое списание завершится.
+func (s *Scheduler) Cancel(ctx context.Context, id string) error {
+	if err := s.store.Delete(ctx, id); err != nil {
+		return fmt.Errorf("recurring: can't delete %s: %w", id, err)
+	}
+	return nil
+}
+
+// Run обрабатывает наступившие запуски каждые Interval, пока не отменён ctx.
+func (s *Scheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.ProcessDue(ctx); err != nil && ctx.Err() == nil && s.OnError != nil {
+			s.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue выполняет наступившие и пропущенные запуски и возвращает число успешных списаний.
+func (s *Scheduler) ProcessDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	templates, err := s.store.Claim(ctx, now, s.Lease, s.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("recurring: can't claim templates: %w", err)
+	}
+
+	var charged int
+	var errs []error
+	for _, template := range templates {
+		if ctx.Err() != nil {
+			break
+		}
+
+		n, err := s.process(ctx, template, now)
+		charged += n
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return charged, errors.Join(errs...)
+}
+
+func (s *Scheduler) process(ctx context.Context, template Template, now time.Time) (int, error) {
+	schedule, location, err := template.parse()
+	if err != nil {
+		return 0, fmt.Errorf("recurring: template %s: %w", template.ID, err)
+	}
+
+	// запуски от NextRunAt до now; next — первый запуск после них
+	var due []time.Time
+	next := template.NextRunAt.In(location)
+	if s.CatchUp == CatchUpAll {
+		for !next.IsZero() && !next.After(now) && template.within(next) && len(due) < s.maxCatchUp() {
+			due = append(due, next)
+			next = schedule.Next(next)
+		}
+	} else {
+		// последний запуск ищем без предела MaxCatchUp: иначе после долгого простоя каждая
+		// обработка списывала бы за последний запуск очередной порции. Предел — только для OnSkipped
+		var latest time.Time
+		var skipped []time.Time
+		for !next.IsZero() && !next.After(now) && template.within(next) {
+			if !latest.IsZero() && len(skipped) < s.maxCatchUp() {
+				skipped = append(skipped, latest)
+			}
+			latest = next
+			next = schedule.Next(next)
+		}
+		if !latest.IsZero() {
+			due = append(due, latest)
+		}
+		if len(skipped) > 0 && s.OnSkipped != nil {
+			s.OnSkipped(template, skipped)
+		}
+	}
+
+	var charged int
+	var errs []error
+runs:
+	for _, scheduledAt := range due {
+		run, err := s.charge(ctx, &template, scheduledAt)
+		switch {
+		case err == nil:
+			charged++
+			if s.OnCharged != nil {
+				s.OnCharged(template, run)
+			}
+		case errors.Is(err, ErrDeclined) || softline.IsPermanent(err):
+			if s.OnFailed != nil {
+				s.OnFailed(template, run, err)
+			}
+		default:
+			// временная ошибка: запуск останется следующим и будет повторён с тем же ключом
+			next = scheduledAt
+			errs = append(errs, fmt.Errorf("recurring: template %s run at %s: %w", template.ID, scheduledAt.Format(time.RFC3339), err))
+			break runs
+		}
+	}
+
+	template.NextRunAt = next
+	template.LeaseUntil = time.Time{}
+	template.UpdatedAt = time.Now()
+	if next.IsZero() || !template.within(next) {
+		template.State = StateCompleted
+		if err := s.store.Delete(ctx, template.ID); err != nil {
+			return charged, errors.Join(append(errs, fmt.Errorf("recurring: can't delete %s: %w", template.ID, err))...)
+		}
+		if s.OnCompleted != nil {
+			s.OnCompleted(template)
+		}
+		return charged, errors.Join(errs...)
+	}
+
+	if err := s.store.Save(ctx, template); err != nil {
+		errs = append(errs, fmt.Errorf("recurring: can't save %s: %w", template.ID, err))
+	}
+	return charged, errors.Join(errs...)
+}
+
+// charge выполняет списание за запуск; отклонение платежа со статусом 200 тоже считается ошибкой.
+func (s *Scheduler) charge(ctx context.Context, template *Template, scheduledAt time.Time) (Run, error) {
+	run := Run{
+		TemplateID:  template.ID,
+		ScheduledAt: scheduledAt,
+		// повтор запуска после сбоя процесса не приведёт к двойному списанию
+		IdempotencyKey: fmt.Sprintf("%s:%s", template.ID, scheduledAt.UTC().Format(time.RFC3339)),
+	}
+
+	request := template.Request
+	request.IdempotencyKey = run.IdempotencyKey
+
+	_, response, err := s.client.MakePayment(ctx, request, "")
+	run.Response = response
+	if response != nil {
+		template.LastStatus = response.Status
+		template.LastOrderId = response.OrderId
+	}
+	if err == nil && response != nil && response.Status == softline.StatusDeclined {
+		err = ErrDeclined
+	}
+
+	template.LastRunAt = scheduledAt
+	template.LastError = ""
+	if err != nil {
+		template.LastError = err.Error()
+	} else {
+		template.Runs++
+	}
+	return run, err
+}
+
+func (s *Scheduler) maxCatchUp() int {
+	if s.MaxCatchUp <= 0 {
+		return defaultMaxCatchUp
+	}
+	return s.MaxCatchUp
+}
+
+func (t Template) parse() (Schedule, *time.Location, error) {
+	schedule, err := ParseCron(t.Schedule)
+	if err != nil {
+		return nil, nil, err
+	}
+	location, err := time.LoadLocation(t.Location)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: location %q: %w", ErrInvalidSchedule, t.Location, err)
+	}
+	return schedule, location, nil
+}
+
+func (t Template) within(at time.Time) bool {
+	return t.EndAt.IsZero() || !at.After(t.EndAt)
+}
@@ -0,0 +1,189 @@
+package recurring
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	softline "github.com/dwnGnL/softlinePayment"
+)
+
+// fakeClient возвращает ошибки из errs по очереди, затем успешные списания.
+type fakeClient struct {
+	errs []error
+	keys []string
+}
+
+func (c *fakeClient) MakePayment(_ context.Context, data softline.MakePaymentReq, _ string, _ ...softline.RequestOption) ([]byte, *softline.CreatePaymentResp, error) {
+	c.keys = append(c.keys, data.IdempotencyKey)
+	if len(c.errs) > 0 {
+		err := c.errs[0]
+		c.errs = c.errs[1:]
+		if err != nil {
+			return nil, new(softline.CreatePaymentResp), err
+		}
+	}
+	return nil, &softline.CreatePaymentResp{OrderId: 100, Status: softline.StatusPaid}, nil
+}
+
+// missedTemplate — почасовой шаблон, простоявший missed запусков.
+func missedTemplate(missed int) Template {
+	last := time.Now().UTC().Truncate(time.Hour)
+	return Template{
+		ID:        "tpl",
+		Request:   softline.MakePaymentReq{ParentOrderId: 1},
+		Schedule:  "@hourly",
+		State:     StateActive,
+		NextRunAt: last.Add(-time.Duration(missed-1) * time.Hour),
+	}
+}
+
+func TestCatchUpLatestChargesOnce(t *testing.T) {
+	store := NewMemoryStore()
+	client := &fakeClient{}
+	scheduler := New(store, client)
+
+	var skipped int
+	scheduler.OnSkipped = func(_ Template, missed []time.Time) { skipped += len(missed) }
+
+	template := missedTemplate(240)
+	if err := store.Save(context.Background(), template); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := scheduler.ProcessDue(context.Background()); err != nil {
+			t.Fatalf("ProcessDue: %v", err)
+		}
+	}
+
+	if len(client.keys) != 1 {
+		t.Fatalf("charges = %d (%v), want 1", len(client.keys), client.keys)
+	}
+	latest := time.Now().UTC().Truncate(time.Hour)
+	if want := "tpl:" + latest.Format(time.RFC3339); client.keys[0] != want {
+		t.Fatalf("key = %q, want %q", client.keys[0], want)
+	}
+	if skipped != defaultMaxCatchUp {
+		t.Fatalf("skipped reported = %d, want %d", skipped, defaultMaxCatchUp)
+	}
+
+	saved, _ := store.Get("tpl")
+	if !saved.NextRunAt.Equal(latest.Add(time.Hour)) {
+		t.Fatalf("next run = %s, want %s", saved.NextRunAt, latest.Add(time.Hour))
+	}
+}
+
+func TestCatchUpAllRespectsLimit(t *testing.T) {
+	store := NewMemoryStore()
+	client := &fakeClient{}
+	scheduler := New(store, client)
+	scheduler.CatchUp = CatchUpAll
+	scheduler.MaxCatchUp = 2
+
+	if err := store.Save(context.Background(), missedTemplate(5)); err != nil {
+		t.Fatal(err)
+	}
+
+	charged, err := scheduler.ProcessDue(context.Background())
+	if err != nil || charged != 2 {
+		t.Fatalf("first ProcessDue = %d, %v; want 2", charged, err)
+	}
+	charged, err = scheduler.ProcessDue(context.Background())
+	if err != nil || charged != 2 {
+		t.Fatalf("second ProcessDue = %d, %v; want 2", charged, err)
+	}
+	charged, err = scheduler.ProcessDue(context.Background())
+	if err != nil || charged != 1 {
+		t.Fatalf("third ProcessDue = %d, %v; want 1", charged, err)
+	}
+
+	seen := make(map[string]bool)
+	for _, key := range client.keys {
+		if seen[key] {
+			t.Fatalf("duplicate key %q", key)
+		}
+		seen[key] = true
+	}
+}
+
+func TestTransientErrorRetriesSameRun(t *testing.T) {
+	store := NewMemoryStore()
+	client := &fakeClient{errs: []error{softline.ErrServer}}
+	scheduler := New(store, client)
+
+	if err := store.Save(context.Background(), missedTemplate(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := scheduler.ProcessDue(context.Background()); !errors.Is(err, softline.ErrServer) {
+		t.Fatalf("ProcessDue = %v, want %v", err, softline.ErrServer)
+	}
+	if charged, err := scheduler.ProcessDue(context.Background()); err != nil || charged != 1 {
+		t.Fatalf("retry ProcessDue = %d, %v", charged, err)
+	}
+	if len(client.keys) != 2 || client.keys[0] != client.keys[1] {
+		t.Fatalf("keys = %v, want the same key for the retry", client.keys)
+	}
+}
+
+func TestPermanentErrorSkipsRun(t *testing.T) {
+	store := NewMemoryStore()
+	client := &fakeClient{errs: []error{softline.ErrNotFound}}
+	scheduler := New(store, client)
+
+	failed := 0
+	scheduler.OnFailed = func(Template, Run, error) { failed++ }
+
+	if err := store.Save(context.Background(), missedTemplate(1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := scheduler.ProcessDue(context.Background()); err != nil {
+		t.Fatalf("ProcessDue: %v", err)
+	}
+	if failed != 1 {
+		t.Fatalf("OnFailed calls = %d, want 1", failed)
+	}
+	saved, _ := store.Get("tpl")
+	if !saved.NextRunAt.After(time.Now()) {
+		t.Fatalf("next run = %s, want a future run", saved.NextRunAt)
+	}
+}
+
+func TestScheduleRejectsEmptyWindow(t *testing.T) {
+	scheduler := New(NewMemoryStore(), &fakeClient{})
+
+	_, err := scheduler.Schedule(context.Background(), Template{
+		ID:       "tpl",
+		Schedule: "0 10 1 * *",
+		StartAt:  time.Now(),
+		EndAt:    time.Now().Add(time.Minute),
+	})
+	if !errors.Is(err, softline.ErrValidation) {
+		t.Fatalf("Schedule = %v, want %v", err, softline.ErrValidation)
+	}
+}
+
+func TestUnauthorizedRetriesSameRun(t *testing.T) {
+	store := NewMemoryStore()
+	client := &fakeClient{errs: []error{softline.ErrUnauthorized}}
+	scheduler := New(store, client)
+
+	failed := 0
+	scheduler.OnFailed = func(Template, Run, error) { failed++ }
+
+	if err := store.Save(context.Background(), missedTemplate(1)); err != nil {
+		t.Fatal(err)
+	}
+	// сбой авторизации исправляется обновлением учётных данных, а не пропуском списания
+	if _, err := scheduler.ProcessDue(context.Background()); !errors.Is(err, softline.ErrUnauthorized) {
+		t.Fatalf("ProcessDue = %v, want %v", err, softline.ErrUnauthorized)
+	}
+	if failed != 0 {
+		t.Fatalf("OnFailed calls = %d, want 0", failed)
+	}
+	if charged, err := scheduler.ProcessDue(context.Background()); err != nil || charged != 1 {
+		t.Fatalf("retry ProcessDue = %d, %v", charged, err)
+	}
+}
@@ -0,0 +1,55 @@
+package recurring
+
+import (
+	"context"
+	"time"
+
+	"github.com/dwnGnL/softlinePayment/internal/lease"
+)
+
+// Store хранит шаблоны списаний и время следующего запуска. Чтобы списания
+// не терялись при перезагрузке хоста, реализация должна быть персистентной.
+type Store interface {
+	Save(ctx context.Context, template Template) error
+	// Claim атомарно выбирает до limit активных шаблонов с NextRunAt <= now, не занятых
+	// другим обработчиком, и занимает их до now+lease (LeaseUntil).
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Template, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// MemoryStore — Store в памяти процесса для тестов и разработки.
+type MemoryStore struct {
+	templates *lease.Store[Template]
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		templates: lease.NewStore(lease.Rules[Template]{
+			ID:    func(template Template) string { return template.ID },
+			DueAt: func(template Template) time.Time { return template.NextRunAt },
+			Ready: func(template Template, now time.Time) bool {
+				return template.State == StateActive && !template.LeaseUntil.After(now)
+			},
+			Lease: func(template *Template, until time.Time) { template.LeaseUntil = until },
+		}),
+	}
+}
+
+func (m *MemoryStore) Save(_ context.Context, template Template) error {
+	m.templates.Put(template)
+	return nil
+}
+
+func (m *MemoryStore) Claim(_ context.Context, now time.Time, lease time.Duration, limit int) ([]Template, error) {
+	return m.templates.Claim(now, lease, limit), nil
+}
+
+func (m *MemoryStore) Delete(_ context.Context, id string) error {
+	m.templates.Delete(id)
+	return nil
+}
+
+// Get возвращает шаблон по идентификатору.
+func (m *MemoryStore) Get(id string) (Template, bool) {
+	return m.templates.Get(id)
+}
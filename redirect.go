@@ -0,0 +1,65 @@
+package softlinePayment
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+)
+
+const RedirectSignatureParam = "signature"
+
+var ErrInvalidRedirectSignature = errors.New("softline: invalid redirect signature")
+
+// RedirectSignature собирает параметры подписи для query-строки return URL: все параметры,
+// кроме signature, в URL-декодированном виде ("+" — пробел), отсортированные по имени,
+// в виде "name=value". Повторяющиеся параметры не допускаются — их порядок не определён.
+func RedirectSignature(secretKey string, query url.Values) (Signature, error) {
+	names := make([]string, 0, len(query))
+	for name, values := range query {
+		if name == RedirectSignatureParam {
+			continue
+		}
+		if len(values) != 1 {
+			return Signature{}, fmt.Errorf("%w: parameter %q is repeated", ErrInvalidRedirectSignature, name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	params := Signature{
+		SecretKey: secretKey,
+		Values:    make([]string, 0, len(names)),
+	}
+	for _, name := range names {
+		params.Values = append(params.Values, name+"="+query.Get(name))
+	}
+	return params, nil
+}
+
+// VerifyRedirect проверяет подпись параметров, с которыми SOM вернул покупателя на
+// страницу успеха или ошибки (например, r.URL.RawQuery). Без успешной проверки
+// параметрам доверять нельзя: их может подменить сам покупатель.
+// Возвращает разобранные параметры без signature.
+func (s *Service) VerifyRedirect(secretKey, rawQuery string) (url.Values, error) {
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%w: can't parse query: %w", ErrInvalidRedirectSignature, err)
+	}
+
+	signature := query.Get(RedirectSignatureParam)
+	if signature == "" {
+		return nil, fmt.Errorf("%w: %s parameter is missing", ErrInvalidRedirectSignature, RedirectSignatureParam)
+	}
+
+	params, err := RedirectSignature(secretKey, query)
+	if err != nil {
+		return nil, err
+	}
+	if !s.VerifySignature(signature, params) {
+		return nil, ErrInvalidRedirectSignature
+	}
+
+	query.Del(RedirectSignatureParam)
+	return query, nil
+}
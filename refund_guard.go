@@ -0,0 +1,142 @@
+package softlinePayment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const defaultRefundKeyTTL = 7 * 24 * time.Hour
+
+var ErrAlreadyRefunded = errors.New("softline: order already refunded")
+
+// RefundKeyStore атомарно резервирует ключ возврата, чтобы повторный запуск задачи
+// не отправил тот же возврат второй раз.
+type RefundKeyStore interface {
+	// Reserve возвращает true, если ключ ещё не использовался и теперь зарезервирован.
+	Reserve(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Release снимает резерв, если возврат не удался и его можно повторить.
+	Release(ctx context.Context, key string) error
+}
+
+// RefundGuard — защита от двойных возвратов.
+type RefundGuard struct {
+	// хранилище ключей; nil — ключи не отслеживаются
+	Keys   RefundKeyStore
+	KeyTTL time.Duration
+	// перед возвратом проверять через PostCheck, сколько по заказу уже возвращено
+	PreCheck bool
+}
+
+// WithRefundGuard включает защиту от двойных возвратов для Refund и RefundPartial.
+func WithRefundGuard(guard RefundGuard) Option {
+	return func(s *Service) {
+		s.refundGuard = &guard
+	}
+}
+
+// MemoryRefundKeyStore — RefundKeyStore в памяти процесса.
+type MemoryRefundKeyStore struct {
+	mu   sync.Mutex
+	keys map[string]time.Time
+}
+
+func NewMemoryRefundKeyStore() *MemoryRefundKeyStore {
+	return &MemoryRefundKeyStore{
+		keys: make(map[string]time.Time),
+	}
+}
+
+func (m *MemoryRefundKeyStore) Reserve(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if expiresAt, ok := m.keys[key]; ok && now.Before(expiresAt) {
+		return false, nil
+	}
+
+	for k, expiresAt := range m.keys {
+		if now.After(expiresAt) {
+			delete(m.keys, k)
+		}
+	}
+	m.keys[key] = now.Add(ttl)
+
+	return true, nil
+}
+
+func (m *MemoryRefundKeyStore) Release(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.keys, key)
+
+	return nil
+}
+
+// guardRefund проверяет, что возврат не дублирует уже сделанный. Ключ без IdempotencyKey
+// задаётся только для полного возврата: он по заказу возможен один раз. Возвращённый ключ
+// отправляется заголовком Idempotency-Key, чтобы SOM тоже не выполнил возврат дважды.
+// Функцию release нужно вызвать, только если SOM точно не выполнил возврат, см. refundNotApplied.
+func (s *Service) guardRefund(ctx context.Context, orderID, key string, amount *Amount) (idempotencyKey string, release func(), err error) {
+	release = func() {}
+	guard := s.refundGuard
+	if guard == nil {
+		return key, release, nil
+	}
+
+	if guard.PreCheck {
+		_, order, err := s.PostCheck(ctx, orderID, "", WithoutCache())
+		if err != nil {
+			return key, release, fmt.Errorf("can't check order before refund: %w", err)
+		}
+		if order.Status == StatusRefunded {
+			return key, release, fmt.Errorf("%w: order %s is %s", ErrAlreadyRefunded, orderID, order.Status)
+		}
+		if amount != nil && !order.Amount.IsZero() {
+			total, err := order.RefundedAmount.Add(*amount)
+			if err == nil && total.Cmp(order.Amount) > 0 {
+				return key, release, fmt.Errorf("%w: order %s has %s of %s refunded, can't refund %s more", ErrAlreadyRefunded, orderID, order.RefundedAmount, order.Amount, amount)
+			}
+		}
+	}
+
+	if key == "" && amount == nil {
+		key = "refund:" + orderID
+	}
+	if guard.Keys == nil || key == "" {
+		return key, release, nil
+	}
+
+	ttl := guard.KeyTTL
+	if ttl <= 0 {
+		ttl = defaultRefundKeyTTL
+	}
+	reserved, err := guard.Keys.Reserve(ctx, key, ttl)
+	if err != nil {
+		return key, release, fmt.Errorf("refund key store: %w", err)
+	}
+	if !reserved {
+		return key, release, fmt.Errorf("%w: refund %s of order %s was already submitted", ErrAlreadyRefunded, key, orderID)
+	}
+
+	return key, func() {
+		if err := guard.Keys.Release(ctx, key); err != nil {
+			s.logger.Warnf("can't release refund key %s: %v", key, err)
+		}
+	}, nil
+}
+
+// refundNotApplied сообщает, что SOM точно не выполнил возврат: запрос отклонён ответом 2xx–4xx
+// или не отправлялся. При таймауте, сетевой ошибке и 5xx исход неизвестен, и ключ остаётся занятым.
+func refundNotApplied(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.HTTPStatus < http.StatusInternalServerError
+	}
+	return errors.Is(err, ErrValidation) || errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrClosed)
+}
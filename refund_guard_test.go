@@ -0,0 +1,83 @@
+package softlinePayment_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	softline "github.com/dwnGnL/softlinePayment"
+	"github.com/dwnGnL/softlinePayment/softlinetest"
+)
+
+func newGuardedService(t *testing.T, guard softline.RefundGuard) (*softline.Service, *softlinetest.Server) {
+	t.Helper()
+
+	server := softlinetest.NewServer()
+	t.Cleanup(server.Close)
+
+	s, err := softline.New(server.Config(), softline.WithRefundGuard(guard))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s, server
+}
+
+func TestRefundGuardSendsKey(t *testing.T) {
+	s, server := newGuardedService(t, softline.RefundGuard{Keys: softline.NewMemoryRefundKeyStore()})
+
+	if _, err := s.Refund(context.Background(), softline.RefundReq{OrderID: "42"}, ""); err != nil {
+		t.Fatalf("Refund: %v", err)
+	}
+	for _, request := range server.Requests() {
+		if request.Route == softlinetest.RouteRefund {
+			if key := request.Header.Get("Idempotency-Key"); key != "refund:42" {
+				t.Fatalf("Idempotency-Key = %q, want refund:42", key)
+			}
+			return
+		}
+	}
+	t.Fatal("refund request not received")
+}
+
+func TestRefundGuardHeldOnAmbiguousError(t *testing.T) {
+	s, server := newGuardedService(t, softline.RefundGuard{Keys: softline.NewMemoryRefundKeyStore()})
+	server.Enqueue(softlinetest.RouteRefund, softlinetest.ServerError())
+
+	if _, err := s.Refund(context.Background(), softline.RefundReq{OrderID: "42"}, ""); !errors.Is(err, softline.ErrServer) {
+		t.Fatalf("Refund = %v, want %v", err, softline.ErrServer)
+	}
+	// SOM мог выполнить возврат: повтор задачи не должен отправить его снова
+	if _, err := s.Refund(context.Background(), softline.RefundReq{OrderID: "42"}, ""); !errors.Is(err, softline.ErrAlreadyRefunded) {
+		t.Fatalf("second Refund = %v, want %v", err, softline.ErrAlreadyRefunded)
+	}
+	if calls := server.Calls(softlinetest.RouteRefund); calls != 1 {
+		t.Fatalf("refund calls = %d, want 1", calls)
+	}
+}
+
+func TestRefundGuardReleasedOnRejection(t *testing.T) {
+	s, server := newGuardedService(t, softline.RefundGuard{Keys: softline.NewMemoryRefundKeyStore()})
+	server.Enqueue(softlinetest.RouteRefund, softlinetest.ValidationError("refund is not allowed yet"))
+
+	if _, err := s.Refund(context.Background(), softline.RefundReq{OrderID: "42"}, ""); !errors.Is(err, softline.ErrValidation) {
+		t.Fatalf("Refund = %v, want %v", err, softline.ErrValidation)
+	}
+	if _, err := s.Refund(context.Background(), softline.RefundReq{OrderID: "42"}, ""); err != nil {
+		t.Fatalf("second Refund: %v", err)
+	}
+	if calls := server.Calls(softlinetest.RouteRefund); calls != 2 {
+		t.Fatalf("refund calls = %d, want 2", calls)
+	}
+}
+
+func TestRefundGuardPreCheck(t *testing.T) {
+	s, server := newGuardedService(t, softline.RefundGuard{PreCheck: true})
+	server.SetDefault(softlinetest.RouteOrder, softlinetest.Response{Body: softlinetest.Order(42, softline.StatusRefunded)})
+
+	if _, err := s.Refund(context.Background(), softline.RefundReq{OrderID: "42"}, ""); !errors.Is(err, softline.ErrAlreadyRefunded) {
+		t.Fatalf("Refund = %v, want %v", err, softline.ErrAlreadyRefunded)
+	}
+	if calls := server.Calls(softlinetest.RouteRefund); calls != 0 {
+		t.Fatalf("refund calls = %d, want 0", calls)
+	}
+}
@@ -0,0 +1,115 @@
+package softlinePayment
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RefundReasonCode — код причины возврата из справочника SOM.
+type RefundReasonCode string
+
+const (
+	RefundReasonCustomerRequest RefundReasonCode = "customer_request"
+	RefundReasonDuplicate       RefundReasonCode = "duplicate"
+	RefundReasonFraud           RefundReasonCode = "fraud"
+	RefundReasonEventCanceled   RefundReasonCode = "event_canceled"
+	RefundReasonNotDelivered    RefundReasonCode = "not_delivered"
+	RefundReasonDefective       RefundReasonCode = "defective"
+	RefundReasonOther           RefundReasonCode = "other"
+)
+
+func (c RefundReasonCode) IsValid() bool {
+	switch c {
+	case RefundReasonCustomerRequest, RefundReasonDuplicate, RefundReasonFraud, RefundReasonEventCanceled,
+		RefundReasonNotDelivered, RefundReasonDefective, RefundReasonOther:
+		return true
+	}
+	return false
+}
+
+// RefundInitiator — кто инициировал возврат.
+type RefundInitiator string
+
+const (
+	RefundInitiatorCustomer RefundInitiator = "customer"
+	RefundInitiatorMerchant RefundInitiator = "merchant"
+	RefundInitiatorSupport  RefundInitiator = "support"
+	RefundInitiatorSystem   RefundInitiator = "system"
+)
+
+func (i RefundInitiator) IsValid() bool {
+	switch i {
+	case RefundInitiatorCustomer, RefundInitiatorMerchant, RefundInitiatorSupport, RefundInitiatorSystem:
+		return true
+	}
+	return false
+}
+
+type CorrectionType string
+
+const (
+	CorrectionSelf        CorrectionType = "self"        // по собственному решению
+	CorrectionInstruction CorrectionType = "instruction" // по предписанию налогового органа
+)
+
+// ReceiptCorrection — основание для чека коррекции, если возврат исправляет ранее пробитый чек.
+type ReceiptCorrection struct {
+	Type           CorrectionType `json:"type"`
+	DocumentDate   string         `json:"document_date"` // YYYY-MM-DD
+	DocumentNumber string         `json:"document_number,omitempty"`
+	Description    string         `json:"description,omitempty"`
+}
+
+func (c *ReceiptCorrection) validate() []error {
+	var errs []error
+
+	switch c.Type {
+	case CorrectionSelf:
+	case CorrectionInstruction:
+		if c.DocumentNumber == "" {
+			errs = append(errs, errors.New("correction document_number is required for instruction corrections"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown correction type %q", c.Type))
+	}
+	if _, err := time.Parse(time.DateOnly, c.DocumentDate); err != nil {
+		errs = append(errs, fmt.Errorf("correction document_date must be YYYY-MM-DD, got %q", c.DocumentDate))
+	}
+	return errs
+}
+
+// validateRefundDetails проверяет типизированные поля возврата; пустые значения допускаются.
+func validateRefundDetails(code RefundReasonCode, initiator RefundInitiator, correction *ReceiptCorrection) []error {
+	var errs []error
+
+	if code != "" && !code.IsValid() {
+		errs = append(errs, fmt.Errorf("unknown refund reason code %q", code))
+	}
+	if initiator != "" && !initiator.IsValid() {
+		errs = append(errs, fmt.Errorf("unknown refund initiator %q", initiator))
+	}
+	if correction != nil {
+		errs = append(errs, correction.validate()...)
+	}
+	return errs
+}
+
+// Validate проверяет запрос частичного возврата до отправки в SOM.
+func (r *PartialRefundReq) Validate() error {
+	var errs []error
+
+	if r.OrderID == "" {
+		errs = append(errs, errors.New("order id is required"))
+	}
+	errs = appendValidation(errs, validatePaymentAmount(r.Amount, r.Currency))
+	if r.Email != "" {
+		errs = appendValidation(errs, validateEmail("email", r.Email))
+	}
+	errs = append(errs, validateRefundDetails(r.ReasonCode, r.Initiator, r.Correction)...)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrValidation, errors.Join(errs...))
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package softlinePayment
+
+import (
+	"context"
+	"net/http"
+)
+
+const getRefund = "/v1/order/%s/refund/%s"
+
+// GetRefund возвращает состояние асинхронного возврата.
+func (s *Service) GetRefund(ctx context.Context, orderID string, refundID string, token string, opts ...RequestOption) (respBody []byte, response *RefundStatusResp, err error) {
+	path, err := endpointPath(getRefund, orderID, refundID)
+	if err != nil {
+		return nil, new(RefundStatusResp), err
+	}
+
+	return call[struct{}, RefundStatusResp](ctx, s, &SendParams{
+		Operation:  "get_refund",
+		Path:       path,
+		HttpMethod: http.MethodGet,
+		AuthNeed:   true,
+	}, nil, token, opts)
+}
@@ -0,0 +1,372 @@
+package softlinePayment
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	reports = "/v1/report"
+	report  = "/v1/report/%s"
+)
+
+var ErrReportFailed = errors.New("softline: report generation failed")
+
+type ReportType string
+
+const (
+	ReportTransactions ReportType = "transactions" // реестр операций за день
+	ReportSettlement   ReportType = "settlement"   // реестр выплат мерчанту
+)
+
+type ReportStatus string
+
+const (
+	ReportPending ReportStatus = "pending"
+	ReportReady   ReportStatus = "ready"
+	ReportFailed  ReportStatus = "failed"
+)
+
+type CreateReportReq struct {
+	IdempotencyKey string     `json:"-"`
+	Type           ReportType `json:"type"`
+	Date           string     `json:"date"` // YYYY-MM-DD
+}
+
+type Report struct {
+	ResponseMeta `json:"-"`
+
+	IdempotencyKey string       `json:"-"`
+	ReportId       string       `json:"report_id"`
+	Type           ReportType   `json:"type"`
+	Date           string       `json:"date"`
+	Status         ReportStatus `json:"status"`
+	DownloadUrl    string       `json:"download_url"`
+	Errors         []Error      `json:"errors,omitempty"`
+}
+
+// ReportRow — строка реестра. Колонки CSV сопоставляются по заголовку, незнакомые попадают в Extra.
+type ReportRow struct {
+	OrderId        int64
+	PaymentId      string
+	OperationType  string
+	Status         PaymentStatus
+	CreateDate     time.Time
+	SettlementDate time.Time
+	Amount         Amount
+	Fee            Amount
+	NetAmount      Amount
+	Currency       string
+	PaymentMethod  string
+	Extra          map[string]string
+}
+
+// CreateReport заказывает формирование реестра за дату. Реестр формируется асинхронно,
+// дождаться его можно через WaitForReport.
+func (s *Service) CreateReport(ctx context.Context, request CreateReportReq, token string, opts ...RequestOption) (respBody []byte, response *Report, err error) {
+	if _, err = time.Parse(time.DateOnly, request.Date); err != nil {
+		return nil, new(Report), fmt.Errorf("%w: report date must be YYYY-MM-DD, got %q", ErrValidation, request.Date)
+	}
+
+	inputs := &SendParams{
+		Operation:      "create_report",
+		Path:           reports,
+		HttpMethod:     http.MethodPost,
+		AuthNeed:       true,
+		Idempotent:     true,
+		IdempotencyKey: request.IdempotencyKey,
+	}
+	respBody, response, err = call[CreateReportReq, Report](ctx, s, inputs, &request, token, opts)
+	response.IdempotencyKey = inputs.IdempotencyKey
+	return
+}
+
+func (s *Service) GetReport(ctx context.Context, reportID string, token string, opts ...RequestOption) (respBody []byte, response *Report, err error) {
+	path, err := endpointPath(report, reportID)
+	if err != nil {
+		return nil, new(Report), err
+	}
+
+	return call[struct{}, Report](ctx, s, &SendParams{
+		Operation:  "get_report",
+		Path:       path,
+		HttpMethod: http.MethodGet,
+		AuthNeed:   true,
+	}, nil, token, opts)
+}
+
+// WaitForReport опрашивает GetReport, пока реестр не будет готов или не истечёт ctx.
+func (s *Service) WaitForReport(ctx context.Context, reportID string, opts PollOptions) (*Report, error) {
+	if opts.Interval <= 0 {
+		opts = DefaultPollOptions()
+	}
+
+	interval := opts.Interval
+	for {
+		_, response, err := s.GetReport(ctx, reportID, "")
+		if err != nil {
+			return response, err
+		}
+		switch response.Status {
+		case ReportReady:
+			return response, nil
+		case ReportFailed:
+			return response, fmt.Errorf("%w: report %s", ErrReportFailed, reportID)
+		}
+
+		if err = sleepCtx(ctx, interval); err != nil {
+			return response, fmt.Errorf("report %s is not ready: %w", reportID, err)
+		}
+		interval = opts.next(interval)
+	}
+}
+
+// DownloadReport скачивает готовый реестр и разбирает все строки. Для больших реестров лучше OpenReport.
+func (s *Service) DownloadReport(ctx context.Context, report *Report) ([]ReportRow, error) {
+	reader, err := s.OpenReport(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var rows []ReportRow
+	for reader.Next() {
+		rows = append(rows, reader.Row())
+	}
+	return rows, reader.Err()
+}
+
+// OpenReport начинает построчное чтение CSV готового реестра:
+//
+//	reader, err := s.OpenReport(ctx, report)
+//	defer reader.Close()
+//	for reader.Next() {
+//		row := reader.Row()
+//	}
+//	if err := reader.Err(); err != nil { ... }
+//
+// Относительная ссылка скачивается из SOM как обычный запрос "download_report": с токеном,
+// повторами, запасными хостами и размыкателем цепи; ответ при этом ограничен Config.MaxResponseBytes.
+// Абсолютная ссылка (например, на внешнее хранилище) читается потоково, как есть.
+func (s *Service) OpenReport(ctx context.Context, report *Report) (*ReportReader, error) {
+	if report.Status != ReportReady || report.DownloadUrl == "" {
+		return nil, fmt.Errorf("softline: report %s is not ready (status %q)", report.ReportId, report.Status)
+	}
+
+	link, err := url.Parse(report.DownloadUrl)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse report download URL: %w", err)
+	}
+
+	var body io.ReadCloser
+	if link.IsAbs() {
+		body, err = s.streamReport(ctx, link)
+	} else {
+		body, err = s.fetchReport(ctx, link)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	csvReader := csv.NewReader(body)
+	csvReader.ReuseRecord = true
+	header, err := csvReader.Read()
+	if err != nil {
+		body.Close()
+		return nil, fmt.Errorf("can't read report header: %w", err)
+	}
+
+	columns := make([]string, len(header))
+	for i, name := range header {
+		columns[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+
+	return &ReportReader{
+		body:    body,
+		csv:     csvReader,
+		columns: columns,
+	}, nil
+}
+
+// fetchReport скачивает реестр по ссылке относительно URI SOM.
+func (s *Service) fetchReport(ctx context.Context, link *url.URL) (io.ReadCloser, error) {
+	token, err := s.resolveToken(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	query := make(map[string]string)
+	for key, values := range link.Query() {
+		query[key] = values[0]
+	}
+
+	respBody, err := s.sendRequest(ctx, &SendParams{
+		Operation: "download_report",
+		// путь из ссылки остаётся внутри URI SOM: ".." не выводит за его пределы
+		Path:        path.Clean("/" + link.EscapedPath()),
+		HttpMethod:  http.MethodGet,
+		AuthNeed:    true,
+		Token:       token,
+		QueryParams: query,
+		Headers:     map[string]string{"Accept": "text/csv"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(respBody)), nil
+}
+
+// streamReport открывает реестр во внешнем хранилище без токена SOM.
+func (s *Service) streamReport(ctx context.Context, link *url.URL) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("can't create request! Err: %s", err)
+	}
+	req.Header.Set("Accept", "text/csv")
+	req.Header.Set("User-Agent", s.userAgent)
+	req.Header.Set(sdkVersionHeader, Version)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("can't download report: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		body, _ := readResponseBody(resp, s.cfg().MaxResponseBytes)
+		return nil, newAPIError(resp.StatusCode, body)
+	}
+
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return resp.Body, nil
+	}
+	gz, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("can't open gzip body: %w", err)
+	}
+	return gzipBody{Reader: gz, body: resp.Body}, nil
+}
+
+// gzipBody закрывает и распаковщик, и тело ответа.
+type gzipBody struct {
+	*gzip.Reader
+	body io.Closer
+}
+
+func (b gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
+
+// ReportReader построчно разбирает CSV реестра, не собирая строки в память.
+type ReportReader struct {
+	body    io.Closer
+	csv     *csv.Reader
+	columns []string
+	row     ReportRow
+	line    int
+	err     error
+}
+
+// Next читает следующую строку. Возвращает false в конце реестра или при ошибке.
+func (r *ReportReader) Next() bool {
+	if r.err != nil {
+		return false
+	}
+
+	record, err := r.csv.Read()
+	if err == io.EOF {
+		return false
+	}
+	if err != nil {
+		r.err = fmt.Errorf("can't read report: %w", err)
+		return false
+	}
+
+	r.line++
+	if r.row, err = parseReportRow(r.columns, record); err != nil {
+		r.err = fmt.Errorf("report row %d: %w", r.line, err)
+		return false
+	}
+	return true
+}
+
+// Row возвращает текущую строку.
+func (r *ReportReader) Row() ReportRow {
+	return r.row
+}
+
+// Err возвращает ошибку, остановившую чтение.
+func (r *ReportReader) Err() error {
+	return r.err
+}
+
+func (r *ReportReader) Close() error {
+	return r.body.Close()
+}
+
+func parseReportRow(columns, record []string) (row ReportRow, err error) {
+	for i, value := range record {
+		if i >= len(columns) {
+			break
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		switch column := columns[i]; column {
+		case "order_id":
+			row.OrderId, err = strconv.ParseInt(value, 10, 64)
+		case "payment_id":
+			row.PaymentId = value
+		case "operation_type":
+			row.OperationType = value
+		case "status":
+			row.Status = PaymentStatus(value)
+		case "create_date":
+			row.CreateDate, err = parseReportTime(value)
+		case "settlement_date":
+			row.SettlementDate, err = parseReportTime(value)
+		case "amount":
+			row.Amount, err = ParseAmount(value)
+		case "fee":
+			row.Fee, err = ParseAmount(value)
+		case "net_amount":
+			row.NetAmount, err = ParseAmount(value)
+		case "currency":
+			row.Currency = value
+		case "payment_method":
+			row.PaymentMethod = value
+		default:
+			if row.Extra == nil {
+				row.Extra = make(map[string]string)
+			}
+			row.Extra[column] = value
+		}
+		if err != nil {
+			return row, fmt.Errorf("column %s: %w", columns[i], err)
+		}
+	}
+	return row, nil
+}
+
+func parseReportTime(value string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, time.DateTime, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported time format %q", value)
+}
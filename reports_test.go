@@ -0,0 +1,104 @@
+package softlinePayment_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	softline "github.com/dwnGnL/softlinePayment"
+	"github.com/dwnGnL/softlinePayment/softlinetest"
+)
+
+const reportCSV = "order_id,amount,currency,status\n42,100.50,RUB,paid\n43,7,RUB,refunded\n"
+
+// newReportService поднимает SOM под префиксом /api: реестр отдаётся по /api/v1/report/1/file,
+// остальные маршруты — фейковым сервером softlinetest.
+func newReportService(t *testing.T, report http.HandlerFunc, opts ...softline.Option) *softline.Service {
+	t.Helper()
+
+	fake := softlinetest.NewServer()
+	t.Cleanup(fake.Close)
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/v1/report/1/file", report)
+	mux.Handle("/api/", http.StripPrefix("/api", fake.Server.Config.Handler))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	config := fake.Config()
+	config.URI = server.URL + "/api"
+	s, err := softline.New(config, opts...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func TestOpenReportKeepsBasePath(t *testing.T) {
+	s := newReportService(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("AuthorizationJWT") == "" || r.URL.Query().Get("part") != "1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(reportCSV))
+	})
+
+	rows, err := s.DownloadReport(context.Background(), &softline.Report{
+		ReportId:    "1",
+		Status:      softline.ReportReady,
+		DownloadUrl: "/v1/report/1/file?part=1",
+	})
+	if err != nil {
+		t.Fatalf("DownloadReport: %v", err)
+	}
+	if len(rows) != 2 || rows[0].OrderId != 42 || rows[0].Amount.String() != "100.50" || rows[1].Status != softline.StatusRefunded {
+		t.Fatalf("rows = %+v", rows)
+	}
+}
+
+func TestOpenReportUsesCircuitBreaker(t *testing.T) {
+	var calls atomic.Int32
+	s := newReportService(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}, softline.WithCircuitBreaker(softline.CircuitBreakerSettings{FailureThreshold: 1}))
+
+	report := &softline.Report{ReportId: "1", Status: softline.ReportReady, DownloadUrl: "/v1/report/1/file"}
+	if _, err := s.OpenReport(context.Background(), report); !errors.Is(err, softline.ErrServer) {
+		t.Fatalf("OpenReport = %v, want %v", err, softline.ErrServer)
+	}
+	if _, err := s.OpenReport(context.Background(), report); !errors.Is(err, softline.ErrCircuitOpen) {
+		t.Fatalf("second OpenReport = %v, want %v", err, softline.ErrCircuitOpen)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("report calls = %d, want 1", n)
+	}
+}
+
+func TestOpenReportAbsoluteLink(t *testing.T) {
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("AuthorizationJWT") != "" {
+			t.Error("SOM token sent to external storage")
+		}
+		_, _ = w.Write([]byte(reportCSV))
+	}))
+	t.Cleanup(storage.Close)
+
+	s := newReportService(t, http.NotFound)
+	reader, err := s.OpenReport(context.Background(), &softline.Report{ReportId: "1", Status: softline.ReportReady, DownloadUrl: storage.URL + "/r.csv"})
+	if err != nil {
+		t.Fatalf("OpenReport: %v", err)
+	}
+	defer reader.Close()
+
+	var n int
+	for reader.Next() {
+		n++
+	}
+	if reader.Err() != nil || n != 2 {
+		t.Fatalf("read %d rows, err %v", n, reader.Err())
+	}
+}
@@ -0,0 +1,65 @@
+package softlinePayment
+
+import "time"
+
+// RequestOption переопределяет настройки Service для одного вызова.
+type RequestOption func(inputs *SendParams)
+
+// WithTimeout ограничивает время выполнения вызова, включая повторы.
+func WithTimeout(timeout time.Duration) RequestOption {
+	return func(inputs *SendParams) {
+		inputs.Timeout = timeout
+	}
+}
+
+// WithoutRetries отключает повторы для вызова.
+func WithoutRetries() RequestOption {
+	return func(inputs *SendParams) {
+		inputs.Retry = &RetryPolicy{MaxAttempts: 1}
+	}
+}
+
+// WithRequestRetryPolicy задаёт политику повторов для вызова.
+func WithRequestRetryPolicy(policy RetryPolicy) RequestOption {
+	return func(inputs *SendParams) {
+		inputs.Retry = &policy
+	}
+}
+
+// WithHeader добавляет заголовок к запросу.
+func WithHeader(key, value string) RequestOption {
+	return func(inputs *SendParams) {
+		if inputs.Headers == nil {
+			inputs.Headers = make(map[string]string)
+		}
+		inputs.Headers[key] = value
+	}
+}
+
+// WithIdempotencyKey задаёт ключ идемпотентности и разрешает безопасные повторы запроса.
+func WithIdempotencyKey(key string) RequestOption {
+	return func(inputs *SendParams) {
+		inputs.IdempotencyKey = key
+		inputs.Idempotent = true
+	}
+}
+
+// WithContentType задаёт Content-Type тела запроса вместо JSON.
+func WithContentType(contentType string) RequestOption {
+	return func(inputs *SendParams) {
+		inputs.ContentType = contentType
+	}
+}
+
+// WithContentLength задаёт длину потокового тела; без неё тело передаётся чанками.
+func WithContentLength(length int64) RequestOption {
+	return func(inputs *SendParams) {
+		inputs.ContentLength = length
+	}
+}
+
+func (inputs *SendParams) apply(opts []RequestOption) {
+	for _, opt := range opts {
+		opt(inputs)
+	}
+}
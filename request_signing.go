@@ -0,0 +1,70 @@
+package softlinePayment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	signatureHeader          = "X-Signature"
+	signatureTimestampHeader = "X-Signature-Timestamp"
+	signatureNonceHeader     = "X-Signature-Nonce"
+
+	// потоковое тело заранее не прочитать, поэтому вместо его хеша подписывается маркер
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+)
+
+// serverClock хранит расхождение локальных часов с часами SOM по заголовку Date,
+// чтобы метка времени подписи не выходила за допустимое окно на сервере.
+type serverClock struct {
+	offset atomic.Int64
+}
+
+func (c *serverClock) now() time.Time {
+	return time.Now().Add(time.Duration(c.offset.Load()))
+}
+
+func (c *serverClock) observe(date string) {
+	serverTime, err := http.ParseTime(date)
+	if err != nil {
+		return
+	}
+	// Date передаётся с точностью до секунды, меньшие расхождения не учитываем
+	offset := time.Until(serverTime)
+	if offset > -time.Second && offset < time.Second {
+		offset = 0
+	}
+	c.offset.Store(int64(offset))
+}
+
+// signRequest подписывает запрос HMAC-SHA256 ключом Config.SigningKey (или из Config.Secrets):
+// метка времени, nonce, метод, путь с query и SHA-256 тела через перевод строки.
+// Каждая попытка подписывается заново со своим nonce.
+func (s *Service) signRequest(req *http.Request, reqBody *requestBody, key string) {
+	timestamp := strconv.FormatInt(s.clock.now().Unix(), 10)
+	nonce := newUUID()
+
+	payloadHash := unsignedPayload
+	if reqBody == nil || reqBody.stream == nil {
+		var buf []byte
+		if reqBody != nil {
+			buf = reqBody.buf
+		}
+		sum := sha256.Sum256(buf)
+		payloadHash = hex.EncodeToString(sum[:])
+	}
+
+	message := strings.Join([]string{timestamp, nonce, req.Method, req.URL.RequestURI(), payloadHash}, "\n")
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(message))
+
+	req.Header.Set(signatureTimestampHeader, timestamp)
+	req.Header.Set(signatureNonceHeader, nonce)
+	req.Header.Set(signatureHeader, hex.EncodeToString(mac.Sum(nil)))
+}
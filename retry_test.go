@@ -0,0 +1,168 @@
+package softlinePayment
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		delay time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{" 0 ", 0, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+	}
+
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.value != "" {
+			header.Set("Retry-After", tt.value)
+		}
+		delay, ok := retryAfter(header, now)
+		if delay != tt.delay || ok != tt.ok {
+			t.Errorf("retryAfter(%q) = %s, %v; want %s, %v", tt.value, delay, ok, tt.delay, tt.ok)
+		}
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+
+	for attempt, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond} {
+		if got := policy.backoff(attempt + 1); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", attempt+1, got, want)
+		}
+	}
+	// сдвиг за пределы int64 не должен давать отрицательную задержку
+	if got := policy.backoff(80); got != policy.MaxDelay {
+		t.Errorf("backoff(80) = %s, want %s", got, policy.MaxDelay)
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(1); got < 100*time.Millisecond || got > 150*time.Millisecond {
+			t.Fatalf("backoff with jitter = %s, want within [100ms, 150ms]", got)
+		}
+	}
+
+	if attempts := (RetryPolicy{}).attempts(); attempts != 1 {
+		t.Errorf("zero policy attempts = %d, want 1", attempts)
+	}
+}
+
+// statusSequence отвечает статусами по очереди, последний повторяется.
+func statusSequence(t *testing.T, calls *atomic.Int32, header http.Header, statuses ...int) *Service {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		if n > len(statuses) {
+			n = len(statuses)
+		}
+		for key, values := range header {
+			w.Header()[key] = values
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statuses[n-1])
+		_, _ = w.Write([]byte(`{"order_id": 42, "status": "paid"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	s, err := New(&Config{URI: server.URL, Login: "login", Pass: "pass"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func fastRetry() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = time.Millisecond
+	return policy
+}
+
+func TestRetryTransientStatus(t *testing.T) {
+	var calls atomic.Int32
+	s := statusSequence(t, &calls, nil, http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK)
+	WithRetryPolicy(fastRetry())(s)
+
+	if _, _, err := s.PostCheck(context.Background(), "42", "token"); err != nil {
+		t.Fatalf("PostCheck: %v", err)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Fatalf("calls = %d, want 3", n)
+	}
+}
+
+func TestRetryStopsAtMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	s := statusSequence(t, &calls, nil, http.StatusServiceUnavailable)
+	WithRetryPolicy(fastRetry())(s)
+
+	if _, _, err := s.PostCheck(context.Background(), "42", "token"); !errors.Is(err, ErrServer) {
+		t.Fatalf("PostCheck = %v, want %v", err, ErrServer)
+	}
+	if n := calls.Load(); n != 3 {
+		t.Fatalf("calls = %d, want 3", n)
+	}
+}
+
+func TestNoRetryForNonIdempotentPost(t *testing.T) {
+	var calls atomic.Int32
+	s := statusSequence(t, &calls, nil, http.StatusServiceUnavailable, http.StatusOK)
+	WithRetryPolicy(fastRetry())(s)
+
+	_, _, err := s.Capture(context.Background(), CaptureReq{OrderID: "42"}, "token")
+	if !errors.Is(err, ErrServer) {
+		t.Fatalf("Capture = %v, want %v", err, ErrServer)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("calls = %d, want 1", n)
+	}
+}
+
+func TestRetryAfterOnRateLimit(t *testing.T) {
+	var calls atomic.Int32
+	s := statusSequence(t, &calls, http.Header{"Retry-After": {"0"}}, http.StatusTooManyRequests, http.StatusOK)
+	WithRetryPolicy(fastRetry())(s)
+
+	if _, _, err := s.PostCheck(context.Background(), "42", "token"); err != nil {
+		t.Fatalf("PostCheck: %v", err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("calls = %d, want 2", n)
+	}
+}
+
+func TestRetryAfterAboveLimit(t *testing.T) {
+	var calls atomic.Int32
+	s := statusSequence(t, &calls, http.Header{"Retry-After": {"60"}}, http.StatusTooManyRequests)
+	WithRetryPolicy(fastRetry())(s)
+
+	_, _, err := s.PostCheck(context.Background(), "42", "token")
+	var rateLimited *RateLimitError
+	if !errors.As(err, &rateLimited) || rateLimited.RetryAfter != time.Minute {
+		t.Fatalf("PostCheck = %v, want RateLimitError with RetryAfter 1m", err)
+	}
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("PostCheck = %v, want %v", err, ErrRateLimited)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("calls = %d, want 1", n)
+	}
+}
@@ -0,0 +1,101 @@
+package softlinePayment
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Credentials — секреты, которые SDK запрашивает у SecretProvider.
+// Пустое поле берётся из Config.
+type Credentials struct {
+	Login      string
+	Pass       string
+	SigningKey string
+	SecretKey  string // ключ подписи колбэков, см. webhook.Handler.Secrets
+}
+
+// SecretProvider выдаёт секреты в момент использования: перед авторизацией и подписью
+// каждого запроса. Позволяет хранить их в Vault/KMS и менять без перезапуска процесса.
+// Частые обращения к хранилищу сглаживает NewCachedSecretProvider.
+type SecretProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// SecretProviderFunc позволяет использовать функцию как SecretProvider.
+type SecretProviderFunc func(ctx context.Context) (Credentials, error)
+
+func (f SecretProviderFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// credentials возвращает актуальные секреты: из Config.Secrets поверх статических значений конфига.
+func (s *Service) credentials(ctx context.Context) (Credentials, error) {
+	credentials := Credentials{
+		Login:      s.cfg().Login,
+		Pass:       s.cfg().Pass,
+		SigningKey: s.cfg().SigningKey,
+	}
+	if s.cfg().Secrets == nil {
+		return credentials, nil
+	}
+
+	resolved, err := s.cfg().Secrets.Credentials(ctx)
+	if err != nil {
+		return credentials, fmt.Errorf("softline: can't resolve credentials: %w", err)
+	}
+	if resolved.Login != "" {
+		credentials.Login = resolved.Login
+	}
+	if resolved.Pass != "" {
+		credentials.Pass = resolved.Pass
+	}
+	if resolved.SigningKey != "" {
+		credentials.SigningKey = resolved.SigningKey
+	}
+	credentials.SecretKey = resolved.SecretKey
+	return credentials, nil
+}
+
+// CachedSecretProvider запоминает ответ Provider на TTL; ошибки не кэшируются.
+type CachedSecretProvider struct {
+	Provider SecretProvider
+	TTL      time.Duration
+
+	mu        sync.Mutex
+	cached    Credentials
+	expiresAt time.Time
+}
+
+func NewCachedSecretProvider(provider SecretProvider, ttl time.Duration) *CachedSecretProvider {
+	return &CachedSecretProvider{
+		Provider: provider,
+		TTL:      ttl,
+	}
+}
+
+func (p *CachedSecretProvider) Credentials(ctx context.Context) (Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if time.Now().Before(p.expiresAt) {
+		return p.cached, nil
+	}
+
+	credentials, err := p.Provider.Credentials(ctx)
+	if err != nil {
+		return Credentials{}, err
+	}
+	p.cached = credentials
+	p.expiresAt = time.Now().Add(p.TTL)
+	return credentials, nil
+}
+
+// Invalidate сбрасывает кэш, например после ротации секрета.
+func (p *CachedSecretProvider) Invalidate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.expiresAt = time.Time{}
+}
@@ -1,236 +1,650 @@
 package softlinePayment
 
 import (
-	"bytes"
-	"crypto/sha512"
-	"encoding/hex"
-	"encoding/json"
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
 	"fmt"
-	"io"
-	"log"
+	"net"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// Service — клиент SOM. Безопасен для конкурентного использования: все горутины
+// делят один HTTP-клиент с пулом keep-alive соединений и общий кэш токена.
+// Создавайте один Service на приложение, а не на запрос.
 type Service struct {
-	config *Config
+	live         *atomic.Pointer[liveConfig]
+	updateMu     *sync.Mutex // сериализует UpdateConfig
+	client       HTTPClient
+	logger       Logger
+	tokenStore   TokenStore
+	limiter      RateLimiter
+	breaker      *CircuitBreaker
+	decodeMode   DecodeMode
+	signer       SignatureScheme
+	audit        AuditHook
+	metrics      MetricsHook
+	retry        RetryPolicy
+	baseURL      string // WithBaseURL; пусто — из Config
+	userAgent    string
+	debugLogging bool
+	waiters      *paymentWaiters
+	refundGuard  *RefundGuard
+	clock        *serverClock
+	events       *EventBus
+	journal      Journal
+	cache        *CacheOptions
+	stats        *serviceStats
+	connRecycler *connRecycler
+	shutdown     *shutdown
+
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	telemetry      *telemetry
 }
 
 const (
 	auth          = "/v1/login_check"
 	createPayment = "/v1/payment"
 	makePayment   = "/v1/payment/recurring"
-	getPayment    = "v1/order/"
+	getPayment    = "/v1/order/%s"
 	refund        = "/v1/order/%s/refund"
+	capture       = "/v1/order/%s/capture"
+	cancel        = "/v1/order/%s/cancel"
+
+	idempotencyKeyHeader = "Idempotency-Key"
+	defaultUserAgent     = "softlinePayment-go/" + Version
 )
 
-func New(config *Config) *Service {
-	return &Service{
-		config: config,
+func New(config *Config, opts ...Option) (*Service, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	cfg := config.withDefaults()
+	config = &cfg
+
+	s := &Service{
+		retry:     config.Retry,
+		userAgent: defaultUserAgent,
+		waiters:   newPaymentWaiters(),
+		clock:     new(serverClock),
+		stats:     newServiceStats(),
+		shutdown:  newShutdown(),
+		updateMu:  new(sync.Mutex),
+	}
+	if config.MaxConnAgeSec > 0 {
+		s.connRecycler = newConnRecycler(time.Duration(config.MaxConnAgeSec) * time.Second)
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if _, err := url.Parse(s.baseURL); err != nil {
+		return nil, fmt.Errorf("softline: can't parse base URL: %w", err)
+	}
+
+	if config.DryRun {
+		// в режиме симуляции запросы не уходят в SOM, даже если клиент задан через WithHTTPClient
+		s.client = NewSimulator(config.DryRunOutcome)
+	}
+	if s.client == nil {
+		client, err := newHTTPClient(config)
+		if err != nil {
+			return nil, err
+		}
+		s.client = client
 	}
+	if s.breaker != nil {
+		s.breaker.events = s.events
+	}
+	if s.logger == nil {
+		s.logger = nopLogger{}
+	}
+	if s.signer == nil {
+		// версия уже проверена в Validate
+		s.signer, _ = SignatureSchemeByVersion(config.SignatureVersion)
+	}
+	if s.limiter == nil && config.RateLimitRPS > 0 {
+		s.limiter = NewRateLimiter(config.RateLimitRPS, config.RateLimitBurst)
+	}
+	s.telemetry = newTelemetry(s.tracerProvider, s.meterProvider)
+	if s.metrics == nil {
+		s.metrics = nopMetrics{}
+	}
+	s.live = new(atomic.Pointer[liveConfig])
+	s.live.Store(s.newLiveConfig(config))
+
+	return s, nil
 }
 
-func (s *Service) Auth() (response *AuthResp, err error) {
-	response = new(AuthResp)
+// newHTTPClient создаёт клиент один раз на Service, чтобы соединения переиспользовались между вызовами.
+func newHTTPClient(config *Config) (*http.Client, error) {
+	dialer := &net.Dialer{
+		Timeout:   time.Second * time.Duration(config.DialTimeoutSec),
+		KeepAlive: time.Second * time.Duration(config.KeepAliveSec),
+	}
 
-	// отправка в SOM
-	body := new(bytes.Buffer)
-	if err = json.NewEncoder(body).Encode(AuthReq{
-		Username: s.config.Login,
-		Password: s.config.Pass,
-	}); err != nil {
-		err = fmt.Errorf("can't encode request: %s", err)
-		return
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.IdleConnTimeout = time.Second * time.Duration(config.IdleConnTimeoutSec)
+	transport.TLSHandshakeTimeout = time.Second * time.Duration(config.TLSHandshakeTimeoutSec)
+	transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+	transport.MaxConnsPerHost = config.MaxConnsPerHost
+	if transport.MaxIdleConns < config.MaxIdleConnsPerHost {
+		transport.MaxIdleConns = config.MaxIdleConnsPerHost
+	}
+	if config.DisableHTTP2 {
+		// непустой TLSNextProto без "h2" отключает HTTP/2 в net/http
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
 	}
 
-	inputs := SendParams{
-		Path:       auth,
-		HttpMethod: http.MethodPost,
-		Response:   response,
-		Body:       body,
+	if config.TLS != nil {
+		tlsConfig, err := config.TLS.build()
+		if err != nil {
+			return nil, fmt.Errorf("softline: %w", err)
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   time.Second * time.Duration(config.RequestTimeoutSec),
+	}, nil
+}
+
+// Token возвращает закэшированный JWT, при необходимости обновляя его.
+func (s *Service) Token(ctx context.Context) (string, error) {
+	return s.current().tokens.Token(ctx)
+}
+
+// resolveToken подставляет закэшированный токен, если вызывающий не передал свой.
+func (s *Service) resolveToken(ctx context.Context, token string) (string, error) {
+	if token != "" {
+		return token, nil
+	}
+
+	return s.current().tokens.Token(ctx)
+}
+
+func (s *Service) Auth(ctx context.Context, opts ...RequestOption) (response *AuthResp, err error) {
+	credentials, err := s.credentials(ctx)
+	if err != nil {
+		return new(AuthResp), err
 	}
 
-	if _, err = sendRequest(s.config, &inputs); err != nil {
+	// отправка в SOM
+	_, response, err = call[AuthReq, AuthResp](ctx, s, &SendParams{
+		Operation:  "auth",
+		Path:       auth,
+		HttpMethod: http.MethodPost,
+	}, &AuthReq{
+		Username: credentials.Login,
+		Password: credentials.Pass,
+	}, "", opts)
+	if err != nil {
 		return
 	}
 
-	response.Date = inputs.Date
+	response.Date = response.ResponseMeta.Date
 
 	return
 }
 
-func sendRequest(config *Config, inputs *SendParams) (respBody []byte, err error) {
+func (s *Service) sendRequest(ctx context.Context, inputs *SendParams) (respBody []byte, err error) {
+	ctx, done, err := s.shutdown.begin(ctx)
+	defer done()
+	if err != nil {
+		return nil, err
+	}
+
+	if inputs.RequestID == "" {
+		if requestID, ok := RequestIDFromContext(ctx); ok {
+			inputs.RequestID = requestID
+		} else {
+			inputs.RequestID = newUUID()
+		}
+	}
+
 	defer func() {
 		if err != nil {
-			err = fmt.Errorf("softline! SendRequest: %v", err)
+			err = fmt.Errorf("softline! SendRequest (request_id %s): %w", inputs.RequestID, err)
 		}
 	}()
 
-	baseURL, err := url.Parse(config.URI)
-	if err != nil {
-		return respBody, fmt.Errorf("can't parse URI from config: %w", err)
+	if body, ok := s.cachedResponse(ctx, inputs); ok {
+		inputs.HttpCode = http.StatusOK
+		inputs.Meta = ResponseMeta{
+			HTTPStatus:  http.StatusOK,
+			RequestID:   inputs.RequestID,
+			Environment: s.cfg().Environment,
+			Cached:      true,
+		}
+		return body, nil
 	}
 
-	// Добавляем путь из inputs.Path к базовому URL
-	baseURL.Path += inputs.Path
-
-	// Устанавливаем параметры запроса из queryParams
-	query := baseURL.Query()
-	for key, value := range inputs.QueryParams {
-		query.Set(key, value)
+	if inputs.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, inputs.Timeout)
+		defer cancel()
 	}
-	baseURL.RawQuery = query.Encode()
 
-	finalUrl := baseURL.String()
+	s.maybeRecycleConns()
 
-	log.Println("url: ", finalUrl)
+	ctx, finish := s.telemetry.start(ctx, inputs)
+	started := time.Now()
+	defer func() {
+		finish(err)
+		duration := time.Since(started)
+		s.metrics.RequestDone(inputs.Operation, inputs.HttpCode, duration, err)
+		s.stats.requestDone(duration, err)
+	}()
 
-	req, err := http.NewRequest(inputs.HttpMethod, finalUrl, inputs.Body)
-	if err != nil {
-		return respBody, fmt.Errorf("can't create request! Err: %s", err)
-	}
+	live := s.current()
+	finalUrls := make([]string, 0, 1+len(live.fallbackURLs))
+	for i, base := range append([]string{live.baseURL}, live.fallbackURLs...) {
+		baseURL, err := joinURL(base, inputs.Path, inputs.QueryParams)
+		if err != nil {
+			return respBody, err
+		}
 
-	req.Header.Set("Content-Type", "application/json; charset=utf-8")
-	req.Header.Set("Accept", "application/json")
+		if i == 0 {
+			s.logger.Debugf("request: %s %s", inputs.HttpMethod, redactURL(baseURL))
+		}
 
-	if inputs.AuthNeed {
-		req.Header.Set("AuthorizationJWT", fmt.Sprintf("Bearer %v", inputs.Token))
+		if err = guardEnvironment(s.cfg().Environment, baseURL, inputs.HttpMethod); err != nil {
+			return respBody, err
+		}
+
+		finalUrls = append(finalUrls, baseURL.String())
 	}
 
-	httpClient := http.Client{
-		Transport: &http.Transport{
-			IdleConnTimeout: time.Second * time.Duration(config.IdleConnTimeoutSec),
-		},
-		Timeout: time.Second * time.Duration(config.RequestTimeoutSec),
+	// тело буферизуем, чтобы его можно было отправить повторно; потоковое — только если умеет Seek
+	reqBody, err := newRequestBody(inputs)
+	if err != nil {
+		return respBody, err
 	}
 
-	resp, err := httpClient.Do(req)
+	complete, err := s.journalBegin(ctx, inputs, reqBody)
 	if err != nil {
-		return respBody, fmt.Errorf("can't do request! Err: %s", err)
+		return respBody, err
 	}
-	defer resp.Body.Close()
+	defer func() { complete(err) }()
 
-	inputs.HttpCode = resp.StatusCode
+	resp, respBody, err := s.doWithRetry(ctx, finalUrls, reqBody, inputs)
+
+	// протухший токен: авторизуемся заново и повторяем запрос один раз
+	if err == nil && resp.StatusCode == http.StatusUnauthorized && inputs.AuthNeed && reqBody.replayable() {
+		s.logger.Infof("got 401 on %s %s, re-authenticating", inputs.HttpMethod, inputs.Path)
+
+		if tokErr := s.current().tokens.Invalidate(ctx); tokErr != nil {
+			s.logger.Warnf("can't invalidate token: %v", tokErr)
+		}
 
-	respBody, err = io.ReadAll(resp.Body)
+		token, tokErr := s.current().tokens.Token(ctx)
+		if tokErr != nil {
+			return respBody, fmt.Errorf("can't re-authenticate after 401: %w", tokErr)
+		}
+
+		inputs.Token = token
+		resp, respBody, err = s.doWithRetry(ctx, finalUrls, reqBody, inputs)
+	}
 	if err != nil {
-		return respBody, fmt.Errorf("can't read response body! Err: %w", err)
+		s.logger.Warnf("request failed: %s %s: %v", inputs.HttpMethod, inputs.Path, err)
+		return respBody, err
 	}
 
-	if resp.StatusCode == http.StatusInternalServerError {
-		return respBody, fmt.Errorf("error: %v", string(respBody))
-	}
+	s.logger.Debugf("response: %s %s: %d", inputs.HttpMethod, inputs.Path, resp.StatusCode)
 
+	inputs.HttpCode = resp.StatusCode
 	inputs.Date = resp.Header.Get("date")
+	inputs.Meta = newResponseMeta(resp)
+	inputs.Meta.Environment = s.cfg().Environment
+	if inputs.Meta.RequestID == "" {
+		inputs.Meta.RequestID = inputs.RequestID
+	}
 
-	if err = json.Unmarshal(respBody, &inputs.Response); err != nil {
-		return respBody, fmt.Errorf("can't unmarshall response: '%v'. Err: %w", string(respBody), err)
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := newAPIError(resp.StatusCode, respBody)
+		apiErr.RequestID = inputs.Meta.RequestID
+		if resp.StatusCode == http.StatusTooManyRequests {
+			delay, _ := retryAfter(resp.Header, time.Now())
+			return respBody, &RateLimitError{APIError: apiErr, RetryAfter: delay}
+		}
+		return respBody, apiErr
+	}
+
+	// SOM может вернуть бизнес-ошибку со статусом 200
+	if apiErr := newEnvelopeError(resp.StatusCode, respBody); apiErr != nil {
+		apiErr.RequestID = inputs.Meta.RequestID
+		return respBody, apiErr
 	}
+
+	s.updateCache(ctx, inputs, respBody)
+
 	return
 }
 
-func (s *Service) CreatePayment(data CreatePaymentReq, token string) (respBody []byte, response *CreatePaymentResp, err error) {
-	response = new(CreatePaymentResp)
+// doWithRetry выполняет запрос, повторяя его согласно RetryPolicy.
+func (s *Service) doWithRetry(ctx context.Context, finalUrls []string, reqBody *requestBody, inputs *SendParams) (resp *http.Response, respBody []byte, err error) {
+	policy := s.retry
+	if inputs.Retry != nil {
+		policy = *inputs.Retry
+	}
+	canRetry := (inputs.HttpMethod == http.MethodGet || inputs.Idempotent || policy.RetryNonIdempotent) && reqBody.replayable()
 
-	body := new(bytes.Buffer)
-	if err = json.NewEncoder(body).Encode(data); err != nil {
-		err = fmt.Errorf("can't encode request: %s", err)
-		return
+	for attempt := 1; ; attempt++ {
+		resp, respBody, err = s.doFailover(ctx, finalUrls, reqBody, inputs, canRetry)
+
+		if errors.Is(err, ErrCircuitOpen) {
+			return resp, respBody, err
+		}
+
+		if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+			delay, ok := retryAfter(resp.Header, time.Now())
+			if !ok {
+				delay = policy.backoff(attempt)
+			}
+			if !policy.RetryOnRateLimit || attempt >= policy.attempts() || (policy.MaxRetryAfter > 0 && delay > policy.MaxRetryAfter) {
+				return resp, respBody, err
+			}
+
+			s.logger.Infof("got 429 on %s %s, retrying in %s", inputs.HttpMethod, inputs.Path, delay)
+			s.metrics.Retry(inputs.Operation, attempt+1)
+			s.stats.retried()
+			s.events.Publish(LifecycleEvent{Type: EventRequestRetried, Operation: inputs.Operation, Attempt: attempt + 1})
+			if sleepErr := sleepCtx(ctx, delay); sleepErr != nil {
+				return resp, respBody, sleepErr
+			}
+			continue
+		}
+
+		retryable := err != nil || policy.retryableStatus(resp.StatusCode)
+		if !retryable || !canRetry || attempt >= policy.attempts() {
+			return resp, respBody, err
+		}
+
+		s.metrics.Retry(inputs.Operation, attempt+1)
+		s.stats.retried()
+		s.events.Publish(LifecycleEvent{Type: EventRequestRetried, Operation: inputs.Operation, Attempt: attempt + 1})
+		if sleepErr := sleepCtx(ctx, policy.backoff(attempt)); sleepErr != nil {
+			if err == nil {
+				err = sleepErr
+			}
+			return resp, respBody, err
+		}
+	}
+}
+
+func (s *Service) doRequest(ctx context.Context, finalUrl string, reqBody *requestBody, inputs *SendParams) (resp *http.Response, respBody []byte, err error) {
+	if s.limiter != nil {
+		if err = s.limiter.Wait(ctx); err != nil {
+			return nil, nil, fmt.Errorf("rate limiter: %w", err)
+		}
 	}
 
-	inputs := SendParams{
-		Path:       createPayment,
-		HttpMethod: http.MethodPost,
-		Token:      token,
-		AuthNeed:   true,
-		Response:   response,
-		Body:       body,
+	if s.breaker != nil {
+		if err = s.breaker.Allow(); err != nil {
+			return nil, nil, err
+		}
+		defer func() {
+			// отмена вызывающим (в том числе проигравший хедж-запрос) — не сбой SOM
+			if errors.Is(err, context.Canceled) {
+				s.breaker.Cancel()
+				return
+			}
+			s.breaker.Record(err == nil && resp.StatusCode < http.StatusInternalServerError)
+		}()
 	}
 
-	if respBody, err = sendRequest(s.config, &inputs); err != nil {
-		return
+	// RequestTimeoutSec — общий потолок клиента, отдельные операции можно ограничить строже
+	if timeout := s.cfg().operationTimeout(inputs.Operation); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
 
-	return
+	body, contentLength, err := reqBody.reader()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, inputs.HttpMethod, finalUrl, body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't create request! Err: %s", err)
+	}
+	if contentLength > 0 {
+		req.ContentLength = contentLength
+	}
+
+	contentType := inputs.ContentType
+	if contentType == "" {
+		contentType = "application/json; charset=utf-8"
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("User-Agent", s.userAgent)
+	req.Header.Set(sdkVersionHeader, Version)
+	req.Header.Set(requestIDHeader, inputs.RequestID)
+	if s.cfg().HostHeader != "" {
+		req.Host = s.cfg().HostHeader
+	}
+
+	for key, value := range inputs.Headers {
+		req.Header.Set(key, value)
+	}
+
+	if inputs.IdempotencyKey != "" {
+		req.Header.Set(idempotencyKeyHeader, inputs.IdempotencyKey)
+	}
+
+	if inputs.AuthNeed {
+		req.Header.Set("AuthorizationJWT", fmt.Sprintf("Bearer %v", inputs.Token))
+	}
+
+	if s.cfg().SigningKey != "" || s.cfg().Secrets != nil {
+		credentials, err := s.credentials(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		if credentials.SigningKey != "" {
+			s.signRequest(req, reqBody, credentials.SigningKey)
+		}
+	}
+
+	if s.debugLogging {
+		s.logger.Debugf("request %s: %s %s headers: [%s] body: %s", inputs.RequestID, req.Method, redactURL(req.URL), redactHeaders(req.Header), reqBody.debugString())
+	}
+
+	resp, err = s.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't do request! Err: %w", err)
+	}
+	defer resp.Body.Close()
+	s.clock.observe(resp.Header.Get("Date"))
+
+	respBody, err = readResponseBody(resp, s.cfg().MaxResponseBytes)
+	if err != nil {
+		return nil, respBody, fmt.Errorf("can't read response body! Err: %w", err)
+	}
+
+	if s.debugLogging {
+		s.logger.Debugf("response %s: %s %s: %d headers: [%s] body: %s", inputs.RequestID, req.Method, inputs.Path, resp.StatusCode, redactHeaders(resp.Header), redactBody(respBody))
+	}
+
+	return resp, respBody, nil
 }
 
-func (s *Service) MakePayment(data MakePaymentReq, token string) (respBody []byte, response *CreatePaymentResp, err error) {
-	response = new(CreatePaymentResp)
+func (s *Service) CreatePayment(ctx context.Context, data CreatePaymentReq, token string, opts ...RequestOption) (respBody []byte, response *CreatePaymentResp, err error) {
+	if err = data.Validate(); err != nil {
+		return nil, new(CreatePaymentResp), err
+	}
 
-	body := new(bytes.Buffer)
-	if err = json.NewEncoder(body).Encode(data); err != nil {
-		err = fmt.Errorf("can't encode request: %s", err)
-		return
+	inputs := &SendParams{
+		Operation:      "create_payment",
+		Path:           createPayment,
+		HttpMethod:     http.MethodPost,
+		AuthNeed:       true,
+		Idempotent:     true,
+		IdempotencyKey: data.IdempotencyKey,
 	}
+	respBody, response, err = call[CreatePaymentReq, CreatePaymentResp](ctx, s, inputs, &data, token, opts)
+	response.IdempotencyKey = inputs.IdempotencyKey
 
-	inputs := SendParams{
-		Path:       makePayment,
-		HttpMethod: http.MethodPost,
-		Token:      token,
-		Response:   response,
-		AuthNeed:   true,
-		Body:       body,
+	return
+}
+
+func (s *Service) MakePayment(ctx context.Context, data MakePaymentReq, token string, opts ...RequestOption) (respBody []byte, response *CreatePaymentResp, err error) {
+	if err = data.Validate(); err != nil {
+		return nil, new(CreatePaymentResp), err
 	}
 
-	if respBody, err = sendRequest(s.config, &inputs); err != nil {
-		return
+	inputs := &SendParams{
+		Operation:      "make_payment",
+		Path:           makePayment,
+		HttpMethod:     http.MethodPost,
+		AuthNeed:       true,
+		Idempotent:     true,
+		IdempotencyKey: data.IdempotencyKey,
 	}
+	respBody, response, err = call[MakePaymentReq, CreatePaymentResp](ctx, s, inputs, &data, token, opts)
+	response.IdempotencyKey = inputs.IdempotencyKey
 
 	return
 }
 
 func (s *Service) GenerateSignature(params Signature) string {
-	message := fmt.Sprintf("%s;%s;%s;%s;%s;%s;%s", params.SecretKey, params.Event, params.OrderID,
-		params.CreateDate, params.PaymentMethod, params.Currency, params.CustomerEmail)
-	hash := sha512.Sum512([]byte(message))
-	return hex.EncodeToString(hash[:])
+	return s.signer.Sign(params)
 }
 
 func (s *Service) VerifySignature(signature string, params Signature) bool {
 	expectedSignature := s.GenerateSignature(params)
-	return signature == expectedSignature
+	return subtle.ConstantTimeCompare([]byte(strings.ToLower(signature)), []byte(expectedSignature)) == 1
 }
 
-func (s *Service) PostCheck(orderID string, token string) (respBody []byte, response *PaymentResp, err error) {
-	response = new(PaymentResp)
+func (s *Service) PostCheck(ctx context.Context, orderID string, token string, opts ...RequestOption) (respBody []byte, response *PaymentResp, err error) {
+	if err = validateOrderID(orderID); err != nil {
+		return nil, new(PaymentResp), err
+	}
+	path, err := endpointPath(getPayment, orderID)
+	if err != nil {
+		return nil, new(PaymentResp), err
+	}
 
-	inputs := SendParams{
-		Path:       fmt.Sprintf("%v%v", getPayment, orderID),
+	return call[struct{}, PaymentResp](ctx, s, &SendParams{
+		Operation:  "post_check",
+		Path:       path,
 		HttpMethod: http.MethodGet,
-		Token:      token,
 		AuthNeed:   true,
-		Response:   response,
+	}, nil, token, opts)
+}
+
+func (s *Service) Refund(ctx context.Context, request RefundReq, token string, opts ...RequestOption) (response *PaymentResp, err error) {
+	if err = request.Validate(); err != nil {
+		return new(PaymentResp), err
+	}
+	path, err := endpointPath(refund, request.OrderID)
+	if err != nil {
+		return new(PaymentResp), err
 	}
 
-	if respBody, err = sendRequest(s.config, &inputs); err != nil {
+	idempotencyKey, release, err := s.guardRefund(ctx, request.OrderID, request.IdempotencyKey, nil)
+	if err != nil {
+		return new(PaymentResp), err
+	}
+	defer func() {
+		if err != nil && refundNotApplied(err) {
+			release()
+		}
+	}()
+
+	inputs := &SendParams{
+		Operation:      "refund",
+		Path:           path,
+		HttpMethod:     http.MethodPost,
+		AuthNeed:       true,
+		Idempotent:     true,
+		IdempotencyKey: idempotencyKey,
+	}
+
+	// при статусе 200 игнорируем только ошибки разбора тела, бизнес-ошибки возвращаем
+	var apiErr *APIError
+	if _, response, err = call[RefundReq, PaymentResp](ctx, s, inputs, &request, token, opts); err != nil && (inputs.HttpCode != http.StatusOK || errors.As(err, &apiErr)) {
 		return
 	}
 
-	return
+	return response, nil
 }
 
-func (s *Service) Refund(request RefundReq, token string) (response *PaymentResp, err error) {
-	response = new(PaymentResp)
+func (s *Service) RefundPartial(ctx context.Context, request PartialRefundReq, token string, opts ...RequestOption) (respBody []byte, response *RefundResp, err error) {
+	if err = request.Validate(); err != nil {
+		return nil, new(RefundResp), err
+	}
+	path, err := endpointPath(refund, request.OrderID)
+	if err != nil {
+		return nil, new(RefundResp), err
+	}
 
-	body := new(bytes.Buffer)
-	if err = json.NewEncoder(body).Encode(request); err != nil {
-		err = fmt.Errorf("can't encode request: %s", err)
-		return
+	idempotencyKey, release, err := s.guardRefund(ctx, request.OrderID, request.IdempotencyKey, &request.Amount)
+	if err != nil {
+		return nil, new(RefundResp), err
+	}
+	defer func() {
+		if err != nil && refundNotApplied(err) {
+			release()
+		}
+	}()
+
+	return call[PartialRefundReq, RefundResp](ctx, s, &SendParams{
+		Operation:      "refund_partial",
+		Path:           path,
+		HttpMethod:     http.MethodPost,
+		AuthNeed:       true,
+		Idempotent:     true,
+		IdempotencyKey: idempotencyKey,
+	}, &request, token, opts)
+}
+
+func (s *Service) Capture(ctx context.Context, request CaptureReq, token string, opts ...RequestOption) (respBody []byte, response *PaymentResp, err error) {
+	if err = validateOrderID(request.OrderID); err != nil {
+		return nil, new(PaymentResp), err
+	}
+	path, err := endpointPath(capture, request.OrderID)
+	if err != nil {
+		return nil, new(PaymentResp), err
 	}
 
-	inputs := SendParams{
-		Path:       fmt.Sprintf(refund, request.OrderID),
+	return call[CaptureReq, PaymentResp](ctx, s, &SendParams{
+		Operation:  "capture",
+		Path:       path,
 		HttpMethod: http.MethodPost,
-		Token:      token,
 		AuthNeed:   true,
-		Body:       body,
-		Response:   response,
-	}
+	}, &request, token, opts)
+}
 
-	if _, err = sendRequest(s.config, &inputs); err != nil && inputs.HttpCode != http.StatusOK {
-		return
+func (s *Service) Cancel(ctx context.Context, request CancelReq, token string, opts ...RequestOption) (respBody []byte, response *PaymentResp, err error) {
+	if err = validateOrderID(request.OrderID); err != nil {
+		return nil, new(PaymentResp), err
+	}
+	path, err := endpointPath(cancel, request.OrderID)
+	if err != nil {
+		return nil, new(PaymentResp), err
 	}
 
-	return response, nil
+	return call[CancelReq, PaymentResp](ctx, s, &SendParams{
+		Operation:  "cancel",
+		Path:       path,
+		HttpMethod: http.MethodPost,
+		AuthNeed:   true,
+	}, &request, token, opts)
 }
@@ -0,0 +1,98 @@
+package softlinePayment
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrClosed = errors.New("softline: service is closed")
+
+// shutdown учитывает выполняющиеся запросы и фоновые ожидания, чтобы Close мог их дождаться.
+type shutdown struct {
+	// ctx — родитель фоновой работы SDK, отменяется в начале Close
+	ctx    context.Context
+	stop   context.CancelFunc
+	mu     sync.Mutex
+	closed bool
+	nextID int
+	active map[int]context.CancelFunc
+	idle   chan struct{} // закрывается, когда после Close не осталось активных операций
+}
+
+func newShutdown() *shutdown {
+	ctx, stop := context.WithCancel(context.Background())
+	return &shutdown{
+		ctx:    ctx,
+		stop:   stop,
+		active: make(map[int]context.CancelFunc),
+		idle:   make(chan struct{}),
+	}
+}
+
+// begin регистрирует операцию. Возвращённый ctx отменяется, если Close не дождался её
+// до своего дедлайна; done обязателен к вызову.
+func (sd *shutdown) begin(ctx context.Context) (context.Context, func(), error) {
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+
+	if sd.closed {
+		return ctx, func() {}, ErrClosed
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	id := sd.nextID
+	sd.nextID++
+	sd.active[id] = cancel
+
+	return ctx, func() {
+		cancel()
+
+		sd.mu.Lock()
+		defer sd.mu.Unlock()
+		delete(sd.active, id)
+		if sd.closed && len(sd.active) == 0 {
+			sd.closeIdle()
+		}
+	}, nil
+}
+
+// closeIdle вызывается под mu.
+func (sd *shutdown) closeIdle() {
+	select {
+	case <-sd.idle:
+	default:
+		close(sd.idle)
+	}
+}
+
+// Close перестаёт принимать новые запросы (они завершаются ErrClosed), прерывает ожидания
+// CreatePaymentAsync и ждёт выполняющиеся запросы, включая фоновое обновление токена.
+// Если ctx истекает раньше, оставшиеся запросы отменяются, а Close возвращает ошибку ctx.
+// В конце закрываются простаивающие соединения. Сервисы ForMerchant закрываются вместе с родителем.
+func (s *Service) Close(ctx context.Context) error {
+	sd := s.shutdown
+
+	sd.mu.Lock()
+	sd.closed = true
+	if len(sd.active) == 0 {
+		sd.closeIdle()
+	}
+	sd.mu.Unlock()
+	sd.stop()
+
+	var err error
+	select {
+	case <-sd.idle:
+	case <-ctx.Done():
+		err = ctx.Err()
+		sd.mu.Lock()
+		for _, cancel := range sd.active {
+			cancel()
+		}
+		sd.mu.Unlock()
+	}
+
+	s.closeIdleConns()
+	return err
+}
@@ -0,0 +1,75 @@
+package softlinePayment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const (
+	SignatureV1           = "v1"
+	SignatureHMACSHA256V2 = "v2-hmac-sha256"
+)
+
+// SignatureScheme — алгоритм подписи уведомлений SOM.
+type SignatureScheme interface {
+	Version() string
+	Sign(params Signature) string
+}
+
+// WithSignatureScheme задаёт собственную схему подписи вместо Config.SignatureVersion.
+func WithSignatureScheme(scheme SignatureScheme) Option {
+	return func(s *Service) {
+		s.signer = scheme
+	}
+}
+
+// SignatureSchemeByVersion возвращает встроенную схему подписи. Пустая версия — v1.
+func SignatureSchemeByVersion(version string) (SignatureScheme, error) {
+	switch version {
+	case "", SignatureV1:
+		return SHA512Scheme{}, nil
+	case SignatureHMACSHA256V2:
+		return HMACSHA256Scheme{}, nil
+	}
+	return nil, fmt.Errorf("softline: unknown signature version %q", version)
+}
+
+// SHA512Scheme — sha512 от "secret;event;order_id;create_date;payment_method;currency;email".
+type SHA512Scheme struct{}
+
+func (SHA512Scheme) Version() string {
+	return SignatureV1
+}
+
+func (SHA512Scheme) Sign(params Signature) string {
+	message := params.SecretKey + ";" + params.message()
+	hash := sha512.Sum512([]byte(message))
+	return hex.EncodeToString(hash[:])
+}
+
+// HMACSHA256Scheme — HMAC-SHA256 с ключом SecretKey от тех же полей без секрета в сообщении.
+type HMACSHA256Scheme struct{}
+
+func (HMACSHA256Scheme) Version() string {
+	return SignatureHMACSHA256V2
+}
+
+func (HMACSHA256Scheme) Sign(params Signature) string {
+	message := params.message()
+	mac := hmac.New(sha256.New, []byte(params.SecretKey))
+	mac.Write([]byte(message))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// message — подписываемые поля через ";" без секрета.
+func (params Signature) message() string {
+	if params.Values != nil {
+		return strings.Join(params.Values, ";")
+	}
+	return strings.Join([]string{params.Event, params.OrderID, params.CreateDate,
+		params.PaymentMethod, params.Currency, params.CustomerEmail}, ";")
+}
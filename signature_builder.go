@@ -0,0 +1,119 @@
+package softlinePayment
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrUnsupportedSignatureEvent = errors.New("softline: no signature field set for event")
+
+// DefaultSignatureFields — порядок подписываемых полей уведомления по типу события
+// (часть event до первой точки). Вложенные поля записываются через точку.
+var DefaultSignatureFields = map[string][]string{
+	"payment":    {"event", "order_id", "create_date", "payment.payment_method", "currency", "customer.email"},
+	"refund":     {"event", "order_id", "refund_id", "create_date", "amount", "currency"},
+	"chargeback": {"event", "order_id", "chargeback_id", "create_date", "amount", "currency", "reason"},
+}
+
+// SignatureBuilder собирает Signature из полного тела уведомления в зависимости от типа события.
+type SignatureBuilder struct {
+	SecretKey string
+	Fields    map[string][]string
+}
+
+func NewSignatureBuilder(secretKey string) *SignatureBuilder {
+	return &SignatureBuilder{
+		SecretKey: secretKey,
+		Fields:    DefaultSignatureFields,
+	}
+}
+
+// NewSignatureBuilderWithEventDate — builder, который дополнительно подписывает event_date последним полем.
+// Подходит, только если мерчанту в SOM включена такая подпись; тогда webhook.Handler проверяет свежесть колбэка.
+func NewSignatureBuilderWithEventDate(secretKey string) *SignatureBuilder {
+	fields := make(map[string][]string, len(DefaultSignatureFields))
+	for kind, names := range DefaultSignatureFields {
+		fields[kind] = append(names[:len(names):len(names)], "event_date")
+	}
+	return &SignatureBuilder{
+		SecretKey: secretKey,
+		Fields:    fields,
+	}
+}
+
+// Signs сообщает, входит ли поле в подписываемый набор для типа события.
+func (b *SignatureBuilder) Signs(event, field string) bool {
+	kind, _, _ := strings.Cut(event, ".")
+	for _, name := range b.Fields[kind] {
+		if name == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Build разбирает тело уведомления и возвращает параметры для GenerateSignature/VerifySignature.
+func (b *SignatureBuilder) Build(payload []byte) (Signature, error) {
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil {
+		return Signature{}, fmt.Errorf("softline: can't decode notification: %w", err)
+	}
+
+	event, _ := fields["event"].(string)
+	kind, _, _ := strings.Cut(event, ".")
+	names, ok := b.Fields[kind]
+	if !ok {
+		return Signature{}, fmt.Errorf("%w %q", ErrUnsupportedSignatureEvent, event)
+	}
+
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		value, err := signatureValue(fields, name)
+		if err != nil {
+			return Signature{}, err
+		}
+		values = append(values, value)
+	}
+
+	params := Signature{
+		SecretKey: b.SecretKey,
+		Event:     event,
+		Values:    values,
+	}
+	params.OrderID, _ = signatureValue(fields, "order_id")
+	params.CreateDate, _ = signatureValue(fields, "create_date")
+	params.Currency, _ = signatureValue(fields, "currency")
+	return params, nil
+}
+
+func signatureValue(fields map[string]interface{}, name string) (string, error) {
+	var value interface{} = fields
+	for _, key := range strings.Split(name, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return "", nil
+		}
+		value = object[key]
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		if v {
+			return "true", nil
+		}
+		return "false", nil
+	}
+	return "", fmt.Errorf("softline: signature field %q is not a scalar", name)
+}
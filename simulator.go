@@ -0,0 +1,193 @@
+package softlinePayment
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SimulatedOutcome — результат платежей в режиме Config.DryRun.
+type SimulatedOutcome string
+
+const (
+	SimulateSuccess SimulatedOutcome = "success"
+	Simulate3DS     SimulatedOutcome = "3ds"
+	SimulateDecline SimulatedOutcome = "decline"
+)
+
+const (
+	// переопределяет исход для одного вызова, см. WithSimulatedOutcome
+	simulateOutcomeHeader = "X-Softline-Simulate"
+	simFirstOrderID       = 900000000
+	simTokenTTL           = time.Hour
+)
+
+var (
+	simAuth    = regexp.MustCompile(`/v1/login_check$`)
+	simCreate  = regexp.MustCompile(`/v1/payment(/recurring|/token)?$`)
+	simOrder   = regexp.MustCompile(`v1/order/(\d+)$`)
+	simOrderOp = regexp.MustCompile(`/v1/order/(\d+)/(refund|capture|cancel)$`)
+	simThreeDS = regexp.MustCompile(`/v1/order/(\d+)/3ds$`)
+)
+
+// Simulator — HTTPClient, отвечающий правдоподобными заготовками вместо SOM.
+// Подключается через Config.DryRun или WithHTTPClient(NewSimulator(...)); заказы живут в памяти.
+type Simulator struct {
+	Outcome SimulatedOutcome
+
+	mu     sync.Mutex
+	nextID int64
+	orders map[int64]PaymentStatus
+}
+
+func NewSimulator(outcome SimulatedOutcome) *Simulator {
+	if outcome == "" {
+		outcome = SimulateSuccess
+	}
+	return &Simulator{
+		Outcome: outcome,
+		nextID:  simFirstOrderID,
+		orders:  make(map[int64]PaymentStatus),
+	}
+}
+
+// WithSimulatedOutcome задаёт исход платежа для одного вызова в режиме симуляции.
+func WithSimulatedOutcome(outcome SimulatedOutcome) RequestOption {
+	return WithHeader(simulateOutcomeHeader, string(outcome))
+}
+
+func (sim *Simulator) Do(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_, _ = io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+
+	outcome := sim.Outcome
+	if header := req.Header.Get(simulateOutcomeHeader); header != "" {
+		outcome = SimulatedOutcome(header)
+	}
+
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+
+	path := req.URL.Path
+	switch {
+	case simAuth.MatchString(path):
+		return simResponse(req, http.StatusOK, map[string]interface{}{"token": simToken()})
+
+	case req.Method == http.MethodPost && simCreate.MatchString(path):
+		sim.nextID++
+		orderID := sim.nextID
+		body := map[string]interface{}{
+			"order_id":    orderID,
+			"payment_url": fmt.Sprintf("https://sandbox.softlinepayment.com/simulated/%d", orderID),
+		}
+		switch outcome {
+		case Simulate3DS:
+			sim.orders[orderID] = StatusAwaiting3DS
+			body["three_ds"] = ThreeDSChallenge{
+				Version: "2.2.0",
+				AcsUrl:  "https://sandbox.softlinepayment.com/simulated/acs",
+				Creq:    base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(orderID, 10))),
+			}
+		case SimulateDecline:
+			sim.orders[orderID] = StatusDeclined
+		default:
+			sim.orders[orderID] = StatusPaid
+		}
+		body["status"] = sim.orders[orderID]
+		return simResponse(req, http.StatusOK, body)
+
+	case simThreeDS.MatchString(path):
+		orderID, _ := strconv.ParseInt(simThreeDS.FindStringSubmatch(path)[1], 10, 64)
+		status := StatusPaid
+		if outcome == SimulateDecline {
+			status = StatusDeclined
+		}
+		sim.orders[orderID] = status
+		return simResponse(req, http.StatusOK, simOrderBody(orderID, status))
+
+	case simOrderOp.MatchString(path):
+		match := simOrderOp.FindStringSubmatch(path)
+		orderID, _ := strconv.ParseInt(match[1], 10, 64)
+		if _, ok := sim.orders[orderID]; !ok {
+			return simNotFound(req)
+		}
+		status := map[string]PaymentStatus{"refund": StatusRefunded, "capture": StatusPaid, "cancel": StatusCanceled}[match[2]]
+		sim.orders[orderID] = status
+		return simResponse(req, http.StatusOK, simOrderBody(orderID, status))
+
+	case req.Method == http.MethodGet && simOrder.MatchString(path):
+		orderID, _ := strconv.ParseInt(simOrder.FindStringSubmatch(path)[1], 10, 64)
+		status, ok := sim.orders[orderID]
+		if !ok {
+			return simNotFound(req)
+		}
+		return simResponse(req, http.StatusOK, simOrderBody(orderID, status))
+
+	case req.Method == http.MethodGet:
+		return simNotFound(req)
+	}
+
+	// прочие изменяющие вызовы считаем успешными без последствий
+	return simResponse(req, http.StatusOK, map[string]interface{}{})
+}
+
+func simOrderBody(orderID int64, status PaymentStatus) map[string]interface{} {
+	now := time.Now().UTC().Format(time.RFC3339)
+	return map[string]interface{}{
+		"event":       "payment",
+		"event_date":  now,
+		"order_id":    orderID,
+		"status":      status,
+		"create_date": now,
+		"currency":    "RUB",
+		"payment": map[string]interface{}{
+			"payment_method": "card",
+		},
+	}
+}
+
+func simNotFound(req *http.Request) (*http.Response, error) {
+	return simResponse(req, http.StatusNotFound, map[string]interface{}{
+		"errors": []Error{{Error: http.StatusNotFound, Message: "order not found in simulator"}},
+	})
+}
+
+func simResponse(req *http.Request, status int, body interface{}) (*http.Response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	header.Set(requestIDHeader, req.Header.Get(requestIDHeader))
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(payload)),
+		ContentLength: int64(len(payload)),
+		Request:       req,
+	}, nil
+}
+
+func simToken() string {
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	payload := enc.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d,"sim":true}`, time.Now().Add(simTokenTTL).Unix())))
+	return header + "." + payload + ".simulated"
+}
@@ -0,0 +1,369 @@
+package softlinegrpc
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Сообщения softline.proto. Кодируются вручную через protowire, поэтому пакет не
+// зависит от сгенерированного кода; формат на проводе совпадает с protoc.
+
+type CreatePaymentRequest struct {
+	IdempotencyKey     string
+	Currency           string
+	Amount             string
+	ReturnSuccessUrl   string
+	PaymentMethod      string
+	RecurringIndicator bool
+	PaymentId          string
+	PaymentDescription string
+	CustomerEmail      string
+	CustomerFirstName  string
+	CustomerLastName   string
+	Metadata           map[string]string
+}
+
+type CreatePaymentResponse struct {
+	OrderId        int64
+	PaymentUrl     string
+	Status         string
+	IdempotencyKey string
+}
+
+type GetPaymentStatusRequest struct {
+	OrderId string
+}
+
+type PaymentStatus struct {
+	OrderId          int64
+	Status           string
+	Currency         string
+	Amount           string
+	RefundedAmount   string
+	DeclineReason    string
+	ErrorCode        string
+	ErrorDescription string
+}
+
+type RefundRequest struct {
+	OrderId        string
+	IdempotencyKey string
+	Amount         string
+	Currency       string
+	Email          string
+	Description    string
+	ReasonCode     string
+}
+
+type RefundResponse struct {
+	OrderId  string
+	RefundId string
+	Status   string
+	Amount   string
+}
+
+// message — сообщение, которое умеет кодировать Codec.
+type message interface {
+	marshal() []byte
+	unmarshal(b []byte) error
+}
+
+func (m *CreatePaymentRequest) marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, m.IdempotencyKey)
+	b = appendString(b, 2, m.Currency)
+	b = appendString(b, 3, m.Amount)
+	b = appendString(b, 4, m.ReturnSuccessUrl)
+	b = appendString(b, 5, m.PaymentMethod)
+	b = appendBool(b, 6, m.RecurringIndicator)
+	b = appendString(b, 7, m.PaymentId)
+	b = appendString(b, 8, m.PaymentDescription)
+	b = appendString(b, 9, m.CustomerEmail)
+	b = appendString(b, 10, m.CustomerFirstName)
+	b = appendString(b, 11, m.CustomerLastName)
+	for key, value := range m.Metadata {
+		var entry []byte
+		entry = appendString(entry, 1, key)
+		entry = appendString(entry, 2, value)
+		b = protowire.AppendTag(b, 12, protowire.BytesType)
+		b = protowire.AppendBytes(b, entry)
+	}
+	return b
+}
+
+func (m *CreatePaymentRequest) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch num {
+		case 1:
+			return consumeString(b, typ, &m.IdempotencyKey)
+		case 2:
+			return consumeString(b, typ, &m.Currency)
+		case 3:
+			return consumeString(b, typ, &m.Amount)
+		case 4:
+			return consumeString(b, typ, &m.ReturnSuccessUrl)
+		case 5:
+			return consumeString(b, typ, &m.PaymentMethod)
+		case 6:
+			return consumeBool(b, typ, &m.RecurringIndicator)
+		case 7:
+			return consumeString(b, typ, &m.PaymentId)
+		case 8:
+			return consumeString(b, typ, &m.PaymentDescription)
+		case 9:
+			return consumeString(b, typ, &m.CustomerEmail)
+		case 10:
+			return consumeString(b, typ, &m.CustomerFirstName)
+		case 11:
+			return consumeString(b, typ, &m.CustomerLastName)
+		case 12:
+			var entry string
+			n, err := consumeString(b, typ, &entry)
+			if err != nil {
+				return n, err
+			}
+			var key, value string
+			err = consumeFields([]byte(entry), func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+				switch num {
+				case 1:
+					return consumeString(b, typ, &key)
+				case 2:
+					return consumeString(b, typ, &value)
+				}
+				return skipField(b, num, typ)
+			})
+			if err != nil {
+				return n, fmt.Errorf("metadata: %w", err)
+			}
+			if m.Metadata == nil {
+				m.Metadata = make(map[string]string)
+			}
+			m.Metadata[key] = value
+			return n, nil
+		}
+		return skipField(b, num, typ)
+	})
+}
+
+func (m *CreatePaymentResponse) marshal() []byte {
+	var b []byte
+	b = appendInt64(b, 1, m.OrderId)
+	b = appendString(b, 2, m.PaymentUrl)
+	b = appendString(b, 3, m.Status)
+	b = appendString(b, 4, m.IdempotencyKey)
+	return b
+}
+
+func (m *CreatePaymentResponse) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch num {
+		case 1:
+			return consumeInt64(b, typ, &m.OrderId)
+		case 2:
+			return consumeString(b, typ, &m.PaymentUrl)
+		case 3:
+			return consumeString(b, typ, &m.Status)
+		case 4:
+			return consumeString(b, typ, &m.IdempotencyKey)
+		}
+		return skipField(b, num, typ)
+	})
+}
+
+func (m *GetPaymentStatusRequest) marshal() []byte {
+	return appendString(nil, 1, m.OrderId)
+}
+
+func (m *GetPaymentStatusRequest) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		if num == 1 {
+			return consumeString(b, typ, &m.OrderId)
+		}
+		return skipField(b, num, typ)
+	})
+}
+
+func (m *PaymentStatus) marshal() []byte {
+	var b []byte
+	b = appendInt64(b, 1, m.OrderId)
+	b = appendString(b, 2, m.Status)
+	b = appendString(b, 3, m.Currency)
+	b = appendString(b, 4, m.Amount)
+	b = appendString(b, 5, m.RefundedAmount)
+	b = appendString(b, 6, m.DeclineReason)
+	b = appendString(b, 7, m.ErrorCode)
+	b = appendString(b, 8, m.ErrorDescription)
+	return b
+}
+
+func (m *PaymentStatus) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch num {
+		case 1:
+			return consumeInt64(b, typ, &m.OrderId)
+		case 2:
+			return consumeString(b, typ, &m.Status)
+		case 3:
+			return consumeString(b, typ, &m.Currency)
+		case 4:
+			return consumeString(b, typ, &m.Amount)
+		case 5:
+			return consumeString(b, typ, &m.RefundedAmount)
+		case 6:
+			return consumeString(b, typ, &m.DeclineReason)
+		case 7:
+			return consumeString(b, typ, &m.ErrorCode)
+		case 8:
+			return consumeString(b, typ, &m.ErrorDescription)
+		}
+		return skipField(b, num, typ)
+	})
+}
+
+func (m *RefundRequest) marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, m.OrderId)
+	b = appendString(b, 2, m.IdempotencyKey)
+	b = appendString(b, 3, m.Amount)
+	b = appendString(b, 4, m.Currency)
+	b = appendString(b, 5, m.Email)
+	b = appendString(b, 6, m.Description)
+	b = appendString(b, 7, m.ReasonCode)
+	return b
+}
+
+func (m *RefundRequest) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch num {
+		case 1:
+			return consumeString(b, typ, &m.OrderId)
+		case 2:
+			return consumeString(b, typ, &m.IdempotencyKey)
+		case 3:
+			return consumeString(b, typ, &m.Amount)
+		case 4:
+			return consumeString(b, typ, &m.Currency)
+		case 5:
+			return consumeString(b, typ, &m.Email)
+		case 6:
+			return consumeString(b, typ, &m.Description)
+		case 7:
+			return consumeString(b, typ, &m.ReasonCode)
+		}
+		return skipField(b, num, typ)
+	})
+}
+
+func (m *RefundResponse) marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, m.OrderId)
+	b = appendString(b, 2, m.RefundId)
+	b = appendString(b, 3, m.Status)
+	b = appendString(b, 4, m.Amount)
+	return b
+}
+
+func (m *RefundResponse) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch num {
+		case 1:
+			return consumeString(b, typ, &m.OrderId)
+		case 2:
+			return consumeString(b, typ, &m.RefundId)
+		case 3:
+			return consumeString(b, typ, &m.Status)
+		case 4:
+			return consumeString(b, typ, &m.Amount)
+		}
+		return skipField(b, num, typ)
+	})
+}
+
+// значения по умолчанию proto3 на провод не пишутся
+
+func appendString(b []byte, num protowire.Number, value string) []byte {
+	if value == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, value)
+}
+
+func appendBool(b []byte, num protowire.Number, value bool) []byte {
+	if !value {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, 1)
+}
+
+func appendInt64(b []byte, num protowire.Number, value int64) []byte {
+	if value == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, uint64(value))
+}
+
+// consumeFields обходит поля сообщения; field возвращает число прочитанных байт значения.
+func consumeFields(b []byte, field func(num protowire.Number, typ protowire.Type, b []byte) (int, error)) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		n, err := field(num, typ, b)
+		if err != nil {
+			return fmt.Errorf("field %d: %w", num, err)
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func consumeString(b []byte, typ protowire.Type, value *string) (int, error) {
+	if typ != protowire.BytesType {
+		return 0, fmt.Errorf("unexpected wire type %d", typ)
+	}
+	v, n := protowire.ConsumeString(b)
+	if n < 0 {
+		return 0, protowire.ParseError(n)
+	}
+	*value = v
+	return n, nil
+}
+
+func consumeBool(b []byte, typ protowire.Type, value *bool) (int, error) {
+	if typ != protowire.VarintType {
+		return 0, fmt.Errorf("unexpected wire type %d", typ)
+	}
+	v, n := protowire.ConsumeVarint(b)
+	if n < 0 {
+		return 0, protowire.ParseError(n)
+	}
+	*value = protowire.DecodeBool(v)
+	return n, nil
+}
+
+func consumeInt64(b []byte, typ protowire.Type, value *int64) (int, error) {
+	if typ != protowire.VarintType {
+		return 0, fmt.Errorf("unexpected wire type %d", typ)
+	}
+	v, n := protowire.ConsumeVarint(b)
+	if n < 0 {
+		return 0, protowire.ParseError(n)
+	}
+	*value = int64(v)
+	return n, nil
+}
+
+func skipField(b []byte, num protowire.Number, typ protowire.Type) (int, error) {
+	n := protowire.ConsumeFieldValue(num, typ, b)
+	if n < 0 {
+		return 0, protowire.ParseError(n)
+	}
+	return n, nil
+}
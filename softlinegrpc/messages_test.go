@@ -0,0 +1,282 @@
+package softlinegrpc
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// testMessages — по сообщению каждого типа со всеми заполненными полями.
+func testMessages() map[string]message {
+	return map[string]message{
+		"CreatePaymentRequest": &CreatePaymentRequest{
+			IdempotencyKey:     "key-1",
+			Currency:           "RUB",
+			Amount:             "100.50",
+			ReturnSuccessUrl:   "https://shop.example/ok",
+			PaymentMethod:      "card",
+			RecurringIndicator: true,
+			PaymentId:          "p-1",
+			PaymentDescription: "Заказ №1",
+			CustomerEmail:      "buyer@example.com",
+			CustomerFirstName:  "Иван",
+			CustomerLastName:   "Петров",
+			Metadata:           map[string]string{"cart": "42", "source": "app", "empty": ""},
+		},
+		"CreatePaymentResponse": &CreatePaymentResponse{
+			OrderId:        9007199254740993,
+			PaymentUrl:     "https://pay.example/1",
+			Status:         "new",
+			IdempotencyKey: "key-1",
+		},
+		"GetPaymentStatusRequest": &GetPaymentStatusRequest{
+			OrderId: "42",
+		},
+		"PaymentStatus": &PaymentStatus{
+			OrderId:          -1,
+			Status:           "declined",
+			Currency:         "RUB",
+			Amount:           "100.50",
+			RefundedAmount:   "10",
+			DeclineReason:    "insufficient_funds",
+			ErrorCode:        "51",
+			ErrorDescription: "not enough money",
+		},
+		"RefundRequest": &RefundRequest{
+			OrderId:        "42",
+			IdempotencyKey: "refund:42",
+			Amount:         "10.00",
+			Currency:       "RUB",
+			Email:          "buyer@example.com",
+			Description:    "возврат",
+			ReasonCode:     "customer_request",
+		},
+		"RefundResponse": &RefundResponse{
+			OrderId:  "42",
+			RefundId: "r-1",
+			Status:   "success",
+			Amount:   "10.00",
+		},
+	}
+}
+
+// newMessage возвращает пустое сообщение того же типа.
+func newMessage(m message) message {
+	return reflect.New(reflect.TypeOf(m).Elem()).Interface().(message)
+}
+
+func TestMessageRoundTrip(t *testing.T) {
+	for name, want := range testMessages() {
+		t.Run(name, func(t *testing.T) {
+			got := newMessage(want)
+			if err := got.unmarshal(want.marshal()); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("round trip = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestZeroMessageIsEmpty(t *testing.T) {
+	for name, m := range testMessages() {
+		if b := newMessage(m).marshal(); len(b) != 0 {
+			t.Errorf("%s: zero value encoded as %x, want no bytes", name, b)
+		}
+	}
+}
+
+// TestWireCompatibility сверяет ручной кодек с protobuf по дескриптору softline.proto:
+// каждое поле должно разбираться как известное, а ответная кодировка — читаться обратно.
+func TestWireCompatibility(t *testing.T) {
+	file := protoFile(t)
+
+	for name, want := range testMessages() {
+		t.Run(name, func(t *testing.T) {
+			desc := file.Messages().ByName(protoreflect.Name(name))
+			if desc == nil {
+				t.Fatalf("message %s is missing in descriptor", name)
+			}
+
+			dynamic := dynamicpb.NewMessage(desc)
+			if err := proto.Unmarshal(want.marshal(), dynamic); err != nil {
+				t.Fatalf("proto.Unmarshal: %v", err)
+			}
+			if unknown := dynamic.GetUnknown(); len(unknown) > 0 {
+				t.Fatalf("fields not matching softline.proto: %x", unknown)
+			}
+
+			b, err := proto.Marshal(dynamic)
+			if err != nil {
+				t.Fatalf("proto.Marshal: %v", err)
+			}
+			got := newMessage(want)
+			if err := got.unmarshal(b); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("decoded %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalSkipsUnknownFields(t *testing.T) {
+	b := protowire.AppendTag(nil, 99, protowire.VarintType)
+	b = protowire.AppendVarint(b, 7)
+	b = protowire.AppendTag(b, 98, protowire.BytesType)
+	b = protowire.AppendString(b, "future")
+	b = append(b, (&GetPaymentStatusRequest{OrderId: "42"}).marshal()...)
+
+	var request GetPaymentStatusRequest
+	if err := request.unmarshal(b); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if request.OrderId != "42" {
+		t.Fatalf("order id = %q, want 42", request.OrderId)
+	}
+}
+
+func TestUnmarshalMalformed(t *testing.T) {
+	truncated := (&RefundRequest{OrderId: "42"}).marshal()
+	wrongType := protowire.AppendTag(nil, 1, protowire.VarintType)
+	wrongType = protowire.AppendVarint(wrongType, 1)
+
+	for name, b := range map[string][]byte{
+		"truncated":  truncated[:len(truncated)-1],
+		"wrong type": wrongType,
+		"bad tag":    {0xff},
+	} {
+		if err := new(RefundRequest).unmarshal(b); err == nil {
+			t.Errorf("%s: unmarshal succeeded, want error", name)
+		}
+	}
+}
+
+func TestCodec(t *testing.T) {
+	codec := Codec{}
+
+	b, err := codec.Marshal(&GetPaymentStatusRequest{OrderId: "42"})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var request GetPaymentStatusRequest
+	if err := codec.Unmarshal(b, &request); err != nil || request.OrderId != "42" {
+		t.Fatalf("Unmarshal = %+v, %v", request, err)
+	}
+
+	// чужие сообщения на том же сервере кодируются обычным protobuf
+	dynamic := dynamicpb.NewMessage(protoFile(t).Messages().ByName("GetPaymentStatusRequest"))
+	if err := codec.Unmarshal(b, dynamic); err != nil {
+		t.Fatalf("Unmarshal proto.Message: %v", err)
+	}
+	if again, err := codec.Marshal(dynamic); err != nil || string(again) != string(b) {
+		t.Fatalf("Marshal proto.Message = %x, %v; want %x", again, err, b)
+	}
+
+	if _, err := codec.Marshal(struct{}{}); err == nil {
+		t.Fatal("Marshal of unsupported type succeeded")
+	}
+	if err := codec.Unmarshal(b, new(string)); err == nil {
+		t.Fatal("Unmarshal into unsupported type succeeded")
+	}
+}
+
+// protoFile строит дескриптор softline.proto без protoc.
+func protoFile(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+
+	str := descriptorpb.FieldDescriptorProto_TYPE_STRING
+	i64 := descriptorpb.FieldDescriptorProto_TYPE_INT64
+	boolean := descriptorpb.FieldDescriptorProto_TYPE_BOOL
+
+	metadata := field("metadata", 12, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE)
+	metadata.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	metadata.TypeName = proto.String(".softline.v1.CreatePaymentRequest.MetadataEntry")
+
+	createPayment := messageProto("CreatePaymentRequest",
+		field("idempotency_key", 1, str),
+		field("currency", 2, str),
+		field("amount", 3, str),
+		field("return_success_url", 4, str),
+		field("payment_method", 5, str),
+		field("recurring_indicator", 6, boolean),
+		field("payment_id", 7, str),
+		field("payment_description", 8, str),
+		field("customer_email", 9, str),
+		field("customer_first_name", 10, str),
+		field("customer_last_name", 11, str),
+		metadata,
+	)
+	entry := messageProto("MetadataEntry", field("key", 1, str), field("value", 2, str))
+	entry.Options = &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)}
+	createPayment.NestedType = []*descriptorpb.DescriptorProto{entry}
+
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("softline.proto"),
+		Package: proto.String("softline.v1"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			createPayment,
+			messageProto("CreatePaymentResponse",
+				field("order_id", 1, i64),
+				field("payment_url", 2, str),
+				field("status", 3, str),
+				field("idempotency_key", 4, str),
+			),
+			messageProto("GetPaymentStatusRequest",
+				field("order_id", 1, str),
+			),
+			messageProto("PaymentStatus",
+				field("order_id", 1, i64),
+				field("status", 2, str),
+				field("currency", 3, str),
+				field("amount", 4, str),
+				field("refunded_amount", 5, str),
+				field("decline_reason", 6, str),
+				field("error_code", 7, str),
+				field("error_description", 8, str),
+			),
+			messageProto("RefundRequest",
+				field("order_id", 1, str),
+				field("idempotency_key", 2, str),
+				field("amount", 3, str),
+				field("currency", 4, str),
+				field("email", 5, str),
+				field("description", 6, str),
+				field("reason_code", 7, str),
+			),
+			messageProto("RefundResponse",
+				field("order_id", 1, str),
+				field("refund_id", 2, str),
+				field("status", 3, str),
+				field("amount", 4, str),
+			),
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("protodesc.NewFile: %v", err)
+	}
+	return file
+}
+
+func messageProto(name string, fields ...*descriptorpb.FieldDescriptorProto) *descriptorpb.DescriptorProto {
+	return &descriptorpb.DescriptorProto{Name: proto.String(name), Field: fields}
+}
+
+func field(name string, number int32, typ descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		JsonName: proto.String(name),
+		Number:   proto.Int32(number),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     typ.Enum(),
+	}
+}
@@ -0,0 +1,256 @@
+// Package softlinegrpc предоставляет операции softlinePayment как gRPC-сервис
+// softline.v1.PaymentService (см. softline.proto), чтобы сервисы на других языках
+// могли пользоваться интеграцией через sidecar. Пакет — отдельный модуль, чтобы
+// основной модуль SDK не тянул зависимости grpc и protobuf.
+//
+//	server := grpc.NewServer(softlinegrpc.ServerOptions()...)
+//	softlinegrpc.Register(server, softlinegrpc.NewServer(service))
+package softlinegrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	softline "github.com/dwnGnL/softlinePayment"
+)
+
+const serviceName = "softline.v1.PaymentService"
+
+// Payments — операции SDK, которые использует сервер. Ему удовлетворяет *softline.Service.
+type Payments interface {
+	CreatePayment(ctx context.Context, data softline.CreatePaymentReq, token string, opts ...softline.RequestOption) ([]byte, *softline.CreatePaymentResp, error)
+	PostCheck(ctx context.Context, orderID string, token string, opts ...softline.RequestOption) ([]byte, *softline.PaymentResp, error)
+	Refund(ctx context.Context, request softline.RefundReq, token string, opts ...softline.RequestOption) (*softline.PaymentResp, error)
+	RefundPartial(ctx context.Context, request softline.PartialRefundReq, token string, opts ...softline.RequestOption) ([]byte, *softline.RefundResp, error)
+}
+
+// Server реализует softline.v1.PaymentService поверх Payments.
+type Server struct {
+	payments Payments
+}
+
+func NewServer(payments Payments) *Server {
+	return &Server{payments: payments}
+}
+
+// ServerOptions возвращает опции grpc.Server, нужные для сообщений этого пакета.
+// Остальные сервисы на том же сервере продолжают работать с обычным protobuf.
+func ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{grpc.ForceServerCodec(Codec{})}
+}
+
+// Register регистрирует PaymentService на gRPC-сервере.
+func Register(registrar grpc.ServiceRegistrar, server *Server) {
+	registrar.RegisterService(&serviceDesc, server)
+}
+
+func (s *Server) CreatePayment(ctx context.Context, request *CreatePaymentRequest) (*CreatePaymentResponse, error) {
+	amount, err := softline.ParseAmount(request.Amount)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "amount: %v", err)
+	}
+
+	_, response, err := s.payments.CreatePayment(ctx, softline.CreatePaymentReq{
+		IdempotencyKey:     request.IdempotencyKey,
+		Currency:           request.Currency,
+		Amount:             amount,
+		ReturnSuccessUrl:   request.ReturnSuccessUrl,
+		PaymentMethod:      request.PaymentMethod,
+		RecurringIndicator: request.RecurringIndicator,
+		PaymentId:          request.PaymentId,
+		PaymentDescription: request.PaymentDescription,
+		Customer: softline.Customer{
+			Email:     request.CustomerEmail,
+			FirstName: request.CustomerFirstName,
+			LastName:  request.CustomerLastName,
+		},
+		Metadata: request.Metadata,
+	}, "")
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &CreatePaymentResponse{
+		OrderId:        response.OrderId,
+		PaymentUrl:     response.PaymentUrl,
+		Status:         string(response.Status),
+		IdempotencyKey: response.IdempotencyKey,
+	}, nil
+}
+
+func (s *Server) GetPaymentStatus(ctx context.Context, request *GetPaymentStatusRequest) (*PaymentStatus, error) {
+	if request.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+
+	_, payment, err := s.payments.PostCheck(ctx, request.OrderId, "")
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return paymentStatus(payment), nil
+}
+
+func (s *Server) Refund(ctx context.Context, request *RefundRequest) (*RefundResponse, error) {
+	if request.Amount == "" {
+		payment, err := s.payments.Refund(ctx, softline.RefundReq{
+			IdempotencyKey: request.IdempotencyKey,
+			OrderID:        request.OrderId,
+			Email:          request.Email,
+			Description:    request.Description,
+			ReasonCode:     softline.RefundReasonCode(request.ReasonCode),
+		}, "")
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		return &RefundResponse{
+			OrderId: request.OrderId,
+			Status:  string(payment.Status),
+			Amount:  amountString(payment.Amount),
+		}, nil
+	}
+
+	amount, err := softline.ParseAmount(request.Amount)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "amount: %v", err)
+	}
+	_, refund, err := s.payments.RefundPartial(ctx, softline.PartialRefundReq{
+		IdempotencyKey: request.IdempotencyKey,
+		OrderID:        request.OrderId,
+		Email:          request.Email,
+		Description:    request.Description,
+		Amount:         amount,
+		Currency:       request.Currency,
+		ReasonCode:     softline.RefundReasonCode(request.ReasonCode),
+	}, "")
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &RefundResponse{
+		OrderId:  request.OrderId,
+		RefundId: refund.RefundId,
+		Status:   refund.Status,
+		Amount:   amountString(refund.Amount),
+	}, nil
+}
+
+func paymentStatus(payment *softline.PaymentResp) *PaymentStatus {
+	return &PaymentStatus{
+		OrderId:          payment.OrderId,
+		Status:           string(payment.Status),
+		Currency:         payment.Currency,
+		Amount:           amountString(payment.Amount),
+		RefundedAmount:   amountString(payment.RefundedAmount),
+		DeclineReason:    string(payment.DeclineReason()),
+		ErrorCode:        payment.Payment.ErrorCode,
+		ErrorDescription: payment.Payment.ErrorDescription,
+	}
+}
+
+func amountString(amount softline.Amount) string {
+	if amount.IsZero() {
+		return ""
+	}
+	return amount.String()
+}
+
+// toStatus переводит ошибку SDK в код gRPC.
+func toStatus(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, softline.ErrValidation):
+		code = codes.InvalidArgument
+	case errors.Is(err, softline.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, softline.ErrUnauthorized):
+		code = codes.Unauthenticated
+	case errors.Is(err, softline.ErrAlreadyRefunded):
+		code = codes.AlreadyExists
+	case errors.Is(err, softline.ErrRejected):
+		code = codes.FailedPrecondition
+	case errors.Is(err, softline.ErrRateLimited):
+		code = codes.ResourceExhausted
+	case errors.Is(err, softline.ErrCircuitOpen), errors.Is(err, softline.ErrServer):
+		code = codes.Unavailable
+	}
+	return status.Error(code, err.Error())
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*interface {
+		CreatePayment(context.Context, *CreatePaymentRequest) (*CreatePaymentResponse, error)
+		GetPaymentStatus(context.Context, *GetPaymentStatusRequest) (*PaymentStatus, error)
+		Refund(context.Context, *RefundRequest) (*RefundResponse, error)
+	})(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "CreatePayment", Handler: unaryHandler("CreatePayment", (*Server).CreatePayment)},
+		{MethodName: "GetPaymentStatus", Handler: unaryHandler("GetPaymentStatus", (*Server).GetPaymentStatus)},
+		{MethodName: "Refund", Handler: unaryHandler("Refund", (*Server).Refund)},
+	},
+	Metadata: "softline.proto",
+}
+
+// unaryHandler — то, что protoc-gen-go-grpc генерирует для каждого унарного метода.
+func unaryHandler[Req any, Resp any, PReq interface {
+	*Req
+	message
+}](method string, call func(*Server, context.Context, PReq) (Resp, error)) func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		request := PReq(new(Req))
+		if err := dec(request); err != nil {
+			return nil, err
+		}
+		server := srv.(*Server)
+		if interceptor == nil {
+			return call(server, ctx, request)
+		}
+		info := &grpc.UnaryServerInfo{
+			Server:     srv,
+			FullMethod: "/" + serviceName + "/" + method,
+		}
+		return interceptor(ctx, request, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return call(server, ctx, req.(PReq))
+		})
+	}
+}
+
+// Codec кодирует сообщения этого пакета вручную, а остальные — через protobuf.
+type Codec struct{}
+
+var _ encoding.Codec = Codec{}
+
+func (Codec) Marshal(v interface{}) ([]byte, error) {
+	switch m := v.(type) {
+	case message:
+		return m.marshal(), nil
+	case proto.Message:
+		return proto.Marshal(m)
+	}
+	return nil, fmt.Errorf("softlinegrpc: can't marshal %T", v)
+}
+
+func (Codec) Unmarshal(data []byte, v interface{}) error {
+	switch m := v.(type) {
+	case message:
+		return m.unmarshal(data)
+	case proto.Message:
+		return proto.Unmarshal(data, m)
+	}
+	return fmt.Errorf("softlinegrpc: can't unmarshal into %T", v)
+}
+
+func (Codec) Name() string {
+	return "proto"
+}
@@ -0,0 +1,119 @@
+package softlinegrpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	softline "github.com/dwnGnL/softlinePayment"
+)
+
+// fakePayments отвечает фиксированными данными и запоминает последний запрос на платёж.
+type fakePayments struct {
+	created   softline.CreatePaymentReq
+	refundErr error
+}
+
+func (p *fakePayments) CreatePayment(_ context.Context, data softline.CreatePaymentReq, _ string, _ ...softline.RequestOption) ([]byte, *softline.CreatePaymentResp, error) {
+	p.created = data
+	return nil, &softline.CreatePaymentResp{OrderId: 7, PaymentUrl: "https://pay.example/7", Status: softline.StatusNew, IdempotencyKey: data.IdempotencyKey}, nil
+}
+
+func (p *fakePayments) PostCheck(_ context.Context, orderID string, _ string, _ ...softline.RequestOption) ([]byte, *softline.PaymentResp, error) {
+	return nil, nil, fmt.Errorf("%w: order %s", softline.ErrNotFound, orderID)
+}
+
+func (p *fakePayments) Refund(_ context.Context, request softline.RefundReq, _ string, _ ...softline.RequestOption) (*softline.PaymentResp, error) {
+	if p.refundErr != nil {
+		return nil, p.refundErr
+	}
+	return &softline.PaymentResp{Status: softline.StatusRefunded}, nil
+}
+
+func (p *fakePayments) RefundPartial(_ context.Context, request softline.PartialRefundReq, _ string, _ ...softline.RequestOption) ([]byte, *softline.RefundResp, error) {
+	return nil, &softline.RefundResp{RefundId: "r-1", Status: "success", Amount: request.Amount}, nil
+}
+
+func dial(t *testing.T, payments Payments) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(ServerOptions()...)
+	Register(server, NewServer(payments))
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(Codec{})),
+	)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestServerCreatePayment(t *testing.T) {
+	payments := &fakePayments{}
+	conn := dial(t, payments)
+
+	var response CreatePaymentResponse
+	err := conn.Invoke(context.Background(), "/"+serviceName+"/CreatePayment", &CreatePaymentRequest{
+		IdempotencyKey: "key-1",
+		Currency:       "RUB",
+		Amount:         "100.50",
+		Metadata:       map[string]string{"cart": "42"},
+	}, &response)
+	if err != nil {
+		t.Fatalf("CreatePayment: %v", err)
+	}
+
+	if response.OrderId != 7 || response.IdempotencyKey != "key-1" {
+		t.Fatalf("response = %+v", response)
+	}
+	if payments.created.Amount.String() != "100.50" || payments.created.Metadata["cart"] != "42" {
+		t.Fatalf("forwarded request = %+v", payments.created)
+	}
+}
+
+func TestServerErrorCodes(t *testing.T) {
+	payments := &fakePayments{refundErr: fmt.Errorf("%w: order 42 is refunded", softline.ErrAlreadyRefunded)}
+	conn := dial(t, payments)
+
+	tests := []struct {
+		method  string
+		request message
+		code    codes.Code
+	}{
+		{"CreatePayment", &CreatePaymentRequest{Amount: "abc"}, codes.InvalidArgument},
+		{"GetPaymentStatus", &GetPaymentStatusRequest{}, codes.InvalidArgument},
+		{"GetPaymentStatus", &GetPaymentStatusRequest{OrderId: "42"}, codes.NotFound},
+		{"Refund", &RefundRequest{OrderId: "42"}, codes.AlreadyExists},
+	}
+
+	for _, tt := range tests {
+		err := conn.Invoke(context.Background(), "/"+serviceName+"/"+tt.method, tt.request, newMessage(responseFor(tt.method)))
+		if got := status.Code(err); got != tt.code {
+			t.Errorf("%s(%+v) code = %s, want %s", tt.method, tt.request, got, tt.code)
+		}
+	}
+}
+
+func responseFor(method string) message {
+	switch method {
+	case "CreatePayment":
+		return &CreatePaymentResponse{}
+	case "GetPaymentStatus":
+		return &PaymentStatus{}
+	}
+	return &RefundResponse{}
+}
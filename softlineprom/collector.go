@@ -0,0 +1,140 @@
+// Package softlineprom экспортирует метрики softlinePayment в Prometheus. Пакет — отдельный
+// модуль, чтобы основной модуль SDK не зависел от client_golang.
+package softlineprom
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	softline "github.com/dwnGnL/softlinePayment"
+)
+
+const namespace = "softline"
+
+// Collector реализует softline.MetricsHook и prometheus.Collector.
+type Collector struct {
+	requests      *prometheus.CounterVec
+	latency       *prometheus.HistogramVec
+	retries       *prometheus.CounterVec
+	tokenRefresh  *prometheus.CounterVec
+	circuitState  *prometheus.Desc
+	mu            sync.Mutex
+	circuitSource []func() softline.CircuitState
+}
+
+func NewCollector() *Collector {
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Requests to SOM by operation and HTTP status.",
+		}, []string{"operation", "status"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "SOM request latency including retries.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "retries_total",
+			Help:      "Retried SOM requests by operation.",
+		}, []string{"operation"}),
+		tokenRefresh: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "token_refreshes_total",
+			Help:      "Authentication calls by result.",
+		}, []string{"result"}),
+		circuitState: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "circuit_state"),
+			"Circuit breaker state: 0 closed, 1 open, 2 half-open.",
+			nil, nil,
+		),
+	}
+}
+
+// WithPrometheusRegisterer регистрирует общий Collector в reg и подключает его к Service.
+// Несколько Service с одним reg пишут в один Collector. Ошибка регистрации приводит к панике, как в prometheus.MustRegister.
+func WithPrometheusRegisterer(reg prometheus.Registerer) softline.Option {
+	collector := NewCollector()
+	if err := reg.Register(collector); err != nil {
+		var already prometheus.AlreadyRegisteredError
+		if !errors.As(err, &already) {
+			panic(err)
+		}
+		existing, ok := already.ExistingCollector.(*Collector)
+		if !ok {
+			panic(err)
+		}
+		collector = existing
+	}
+
+	return collector.Option()
+}
+
+// Option подключает Collector к Service как MetricsHook, без регистрации.
+func (c *Collector) Option() softline.Option {
+	hook := softline.WithMetricsHook(c)
+	return func(s *softline.Service) {
+		hook(s)
+		c.mu.Lock()
+		c.circuitSource = append(c.circuitSource, s.CircuitState)
+		c.mu.Unlock()
+	}
+}
+
+func (c *Collector) RequestDone(operation string, status int, duration time.Duration, err error) {
+	label := strconv.Itoa(status)
+	if status == 0 {
+		label = "error"
+	}
+	c.requests.WithLabelValues(operation, label).Inc()
+	c.latency.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+func (c *Collector) Retry(operation string, _ int) {
+	c.retries.WithLabelValues(operation).Inc()
+}
+
+func (c *Collector) TokenRefreshed(err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	c.tokenRefresh.WithLabelValues(result).Inc()
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.latency.Describe(ch)
+	c.retries.Describe(ch)
+	c.tokenRefresh.Describe(ch)
+	ch <- c.circuitState
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.latency.Collect(ch)
+	c.retries.Collect(ch)
+	c.tokenRefresh.Collect(ch)
+
+	// при нескольких Service показываем худшее состояние: open, затем half-open
+	state := softline.CircuitClosed
+	c.mu.Lock()
+	for _, source := range c.circuitSource {
+		switch source() {
+		case softline.CircuitOpen:
+			state = softline.CircuitOpen
+		case softline.CircuitHalfOpen:
+			if state == softline.CircuitClosed {
+				state = softline.CircuitHalfOpen
+			}
+		}
+	}
+	c.mu.Unlock()
+	ch <- prometheus.MustNewConstMetric(c.circuitState, prometheus.GaugeValue, float64(state))
+}
@@ -0,0 +1,123 @@
+package softlineprom
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	softline "github.com/dwnGnL/softlinePayment"
+	"github.com/dwnGnL/softlinePayment/softlinetest"
+)
+
+func newService(t *testing.T, server *softlinetest.Server, opts ...softline.Option) *softline.Service {
+	t.Helper()
+
+	s, err := softline.New(server.Config(), opts...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func TestCollectorRecordsRequests(t *testing.T) {
+	server := softlinetest.NewServer()
+	t.Cleanup(server.Close)
+	server.Enqueue(softlinetest.RouteOrder, softlinetest.NotFound())
+
+	collector := NewCollector()
+	s := newService(t, server, collector.Option())
+
+	if _, _, err := s.PostCheck(context.Background(), "42", ""); err == nil {
+		t.Fatal("PostCheck succeeded, want not found")
+	}
+	if _, _, err := s.PostCheck(context.Background(), "42", ""); err != nil {
+		t.Fatalf("PostCheck: %v", err)
+	}
+
+	expected := `
+# HELP softline_requests_total Requests to SOM by operation and HTTP status.
+# TYPE softline_requests_total counter
+softline_requests_total{operation="auth",status="200"} 1
+softline_requests_total{operation="post_check",status="200"} 1
+softline_requests_total{operation="post_check",status="404"} 1
+# HELP softline_token_refreshes_total Authentication calls by result.
+# TYPE softline_token_refreshes_total counter
+softline_token_refreshes_total{result="ok"} 1
+# HELP softline_circuit_state Circuit breaker state: 0 closed, 1 open, 2 half-open.
+# TYPE softline_circuit_state gauge
+softline_circuit_state 0
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"softline_requests_total", "softline_token_refreshes_total", "softline_circuit_state"); err != nil {
+		t.Fatal(err)
+	}
+	if count := testutil.CollectAndCount(collector, "softline_request_duration_seconds"); count != 2 {
+		t.Fatalf("latency series = %d, want 2", count)
+	}
+}
+
+func TestCollectorReportsWorstCircuitState(t *testing.T) {
+	server := softlinetest.NewServer()
+	t.Cleanup(server.Close)
+
+	collector := NewCollector()
+	healthy := newService(t, server, collector.Option())
+	broken := newService(t, server, collector.Option(), softline.WithCircuitBreaker(softline.CircuitBreakerSettings{FailureThreshold: 1}))
+
+	if _, _, err := healthy.PostCheck(context.Background(), "42", ""); err != nil {
+		t.Fatalf("PostCheck: %v", err)
+	}
+	server.Enqueue(softlinetest.RouteOrder, softlinetest.ServerError())
+	if _, _, err := broken.PostCheck(context.Background(), "42", ""); err == nil {
+		t.Fatal("PostCheck succeeded, want server error")
+	}
+
+	expected := `
+# HELP softline_circuit_state Circuit breaker state: 0 closed, 1 open, 2 half-open.
+# TYPE softline_circuit_state gauge
+softline_circuit_state 1
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected), "softline_circuit_state"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithPrometheusRegistererSharesCollector(t *testing.T) {
+	server := softlinetest.NewServer()
+	t.Cleanup(server.Close)
+
+	registry := prometheus.NewPedanticRegistry()
+	first := newService(t, server, WithPrometheusRegisterer(registry))
+	second := newService(t, server, WithPrometheusRegisterer(registry))
+
+	for _, s := range []*softline.Service{first, second} {
+		if _, _, err := s.PostCheck(context.Background(), "42", ""); err != nil {
+			t.Fatalf("PostCheck: %v", err)
+		}
+	}
+
+	expected := `
+# HELP softline_requests_total Requests to SOM by operation and HTTP status.
+# TYPE softline_requests_total counter
+softline_requests_total{operation="auth",status="200"} 2
+softline_requests_total{operation="post_check",status="200"} 2
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "softline_requests_total"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithPrometheusRegistererConflict(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(prometheus.NewCounter(prometheus.CounterOpts{Namespace: namespace, Name: "requests_total", Help: "other"}))
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("WithPrometheusRegisterer did not panic on a conflicting collector")
+		}
+	}()
+	WithPrometheusRegisterer(registry)
+}
@@ -0,0 +1,72 @@
+package softlinetest
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	softline "github.com/dwnGnL/softlinePayment"
+)
+
+// AuthOK — успешный ответ login_check с токеном на час.
+func AuthOK() map[string]interface{} {
+	return map[string]interface{}{
+		"token":         Token(time.Hour),
+		"refresh_token": "refresh-token",
+	}
+}
+
+// PaymentCreated — успешное создание платежа.
+func PaymentCreated(orderID int64) map[string]interface{} {
+	return map[string]interface{}{
+		"payment_url": fmt.Sprintf("https://pay.example.com/order/%d", orderID),
+		"order_id":    orderID,
+	}
+}
+
+// Order — заказ в заданном статусе.
+func Order(orderID int64, status softline.PaymentStatus) map[string]interface{} {
+	now := time.Now().UTC().Format(time.RFC3339)
+	return map[string]interface{}{
+		"event":       "payment",
+		"event_date":  now,
+		"order_id":    orderID,
+		"status":      status,
+		"create_date": now,
+		"currency":    "RUB",
+		"customer": map[string]interface{}{
+			"email": "customer@example.com",
+		},
+		"payment": map[string]interface{}{
+			"payment_method": "card",
+		},
+	}
+}
+
+func errorBody(code int, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"errors": []map[string]interface{}{
+			{"error": code, "message": message},
+		},
+	}
+}
+
+func Unauthorized() Response {
+	return Response{Status: http.StatusUnauthorized, Body: errorBody(401, "Invalid JWT Token")}
+}
+
+func NotFound() Response {
+	return Response{Status: http.StatusNotFound, Body: errorBody(404, "Not found")}
+}
+
+func ValidationError(message string) Response {
+	return Response{Status: http.StatusBadRequest, Body: errorBody(400, message)}
+}
+
+func ServerError() Response {
+	return Response{Status: http.StatusInternalServerError, Body: errorBody(500, "Internal server error")}
+}
+
+func Unavailable() Response {
+	return Response{Status: http.StatusServiceUnavailable, Body: []byte("Service Unavailable")}
+}
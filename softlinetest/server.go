@@ -0,0 +1,210 @@
+// Package softlinetest содержит фейковый сервер SOM для тестов без обращения к песочнице.
+package softlinetest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	softline "github.com/dwnGnL/softlinePayment"
+)
+
+type Route string
+
+const (
+	RouteAuth          Route = "auth"
+	RouteCreatePayment Route = "create_payment"
+	RouteMakePayment   Route = "make_payment"
+	RouteOrder         Route = "order"
+	RouteRefund        Route = "refund"
+	RouteCapture       Route = "capture"
+	RouteCancel        Route = "cancel"
+)
+
+// Response — запрограммированный ответ фейкового сервера.
+// Body сериализуется в JSON, если это не []byte.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   interface{}
+}
+
+// Request — запрос, полученный фейковым сервером.
+type Request struct {
+	Route   Route
+	Method  string
+	Path    string
+	Header  http.Header
+	Body    []byte
+	OrderID string
+}
+
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	responses map[Route][]Response
+	defaults  map[Route]Response
+	requests  []Request
+}
+
+// NewServer запускает фейковый SOM с ответами по умолчанию на все маршруты.
+func NewServer() *Server {
+	s := &Server{
+		responses: make(map[Route][]Response),
+		defaults: map[Route]Response{
+			RouteAuth:          {Status: http.StatusOK, Body: AuthOK()},
+			RouteCreatePayment: {Status: http.StatusOK, Body: PaymentCreated(1)},
+			RouteMakePayment:   {Status: http.StatusOK, Body: PaymentCreated(1)},
+			RouteOrder:         {Status: http.StatusOK, Body: Order(1, softline.StatusPaid)},
+			RouteRefund:        {Status: http.StatusOK, Body: Order(1, softline.StatusRefunded)},
+			RouteCapture:       {Status: http.StatusOK, Body: Order(1, softline.StatusPaid)},
+			RouteCancel:        {Status: http.StatusOK, Body: Order(1, softline.StatusCanceled)},
+		},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+
+	return s
+}
+
+// Config возвращает конфиг, направляющий Service на фейковый сервер.
+func (s *Server) Config() *softline.Config {
+	return &softline.Config{
+		IdleConnTimeoutSec: 5,
+		RequestTimeoutSec:  5,
+		Login:              "test",
+		Pass:               "test",
+		URI:                s.URL,
+	}
+}
+
+// SetDefault задаёт ответ маршрута, возвращаемый, пока очередь Enqueue пуста.
+func (s *Server) SetDefault(route Route, resp Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.defaults[route] = resp
+}
+
+// Enqueue добавляет одноразовые ответы маршрута, отдаваемые по порядку.
+func (s *Server) Enqueue(route Route, resps ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.responses[route] = append(s.responses[route], resps...)
+}
+
+// Requests возвращает копию всех полученных запросов.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// Calls возвращает число запросов к маршруту.
+func (s *Server) Calls(route Route) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, r := range s.requests {
+		if r.Route == route {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	route, orderID, ok := match(r.Method, r.URL.Path)
+	if !ok {
+		writeResponse(w, NotFound())
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Route:   route,
+		Method:  r.Method,
+		Path:    r.URL.Path,
+		Header:  r.Header.Clone(),
+		Body:    body,
+		OrderID: orderID,
+	})
+
+	resp := s.defaults[route]
+	if queue := s.responses[route]; len(queue) > 0 {
+		resp = queue[0]
+		s.responses[route] = queue[1:]
+	}
+	s.mu.Unlock()
+
+	writeResponse(w, resp)
+}
+
+func match(method, path string) (route Route, orderID string, ok bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch {
+	case method == http.MethodPost && path == "/v1/login_check":
+		return RouteAuth, "", true
+	case method == http.MethodPost && path == "/v1/payment":
+		return RouteCreatePayment, "", true
+	case method == http.MethodPost && path == "/v1/payment/recurring":
+		return RouteMakePayment, "", true
+	case len(parts) == 3 && parts[0] == "v1" && parts[1] == "order" && method == http.MethodGet:
+		return RouteOrder, parts[2], true
+	case len(parts) == 4 && parts[0] == "v1" && parts[1] == "order" && method == http.MethodPost:
+		switch parts[3] {
+		case "refund":
+			return RouteRefund, parts[2], true
+		case "capture":
+			return RouteCapture, parts[2], true
+		case "cancel":
+			return RouteCancel, parts[2], true
+		}
+	}
+
+	return "", "", false
+}
+
+func writeResponse(w http.ResponseWriter, resp Response) {
+	for key, values := range resp.Header {
+		for _, v := range values {
+			w.Header().Add(key, v)
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+
+	switch body := resp.Body.(type) {
+	case nil:
+	case []byte:
+		_, _ = w.Write(body)
+	default:
+		_ = json.NewEncoder(w).Encode(body)
+	}
+}
+
+// Token возвращает JWT с заданным сроком жизни; подпись не проверяется.
+func Token(ttl time.Duration) string {
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payload := enc.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, time.Now().Add(ttl).Unix())))
+
+	return header + "." + payload + ".signature"
+}
@@ -0,0 +1,201 @@
+package softlinetest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	softline "github.com/dwnGnL/softlinePayment"
+	"github.com/dwnGnL/softlinePayment/softlinetest"
+)
+
+func newService(t *testing.T) (*softline.Service, *softlinetest.Server) {
+	t.Helper()
+
+	server := softlinetest.NewServer()
+	t.Cleanup(server.Close)
+
+	s, err := softline.New(server.Config())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s, server
+}
+
+func TestAuth(t *testing.T) {
+	s, server := newService(t)
+
+	response, err := s.Auth(context.Background())
+	if err != nil {
+		t.Fatalf("Auth: %v", err)
+	}
+	if response.Token == "" {
+		t.Fatal("Auth returned empty token")
+	}
+	if calls := server.Calls(softlinetest.RouteAuth); calls != 1 {
+		t.Fatalf("auth calls = %d, want 1", calls)
+	}
+}
+
+func TestCreatePayment(t *testing.T) {
+	s, server := newService(t)
+	server.SetDefault(softlinetest.RouteCreatePayment, softlinetest.Response{Body: softlinetest.PaymentCreated(42)})
+
+	_, response, err := s.CreatePayment(context.Background(), softline.CreatePaymentReq{
+		Currency: "RUB",
+		Amount:   softline.NewAmount(10050, 2),
+	}, "")
+	if err != nil {
+		t.Fatalf("CreatePayment: %v", err)
+	}
+	if response.OrderId != 42 || response.PaymentUrl == "" {
+		t.Fatalf("CreatePayment = order %d, url %q", response.OrderId, response.PaymentUrl)
+	}
+	// без токена Service сначала авторизуется
+	if calls := server.Calls(softlinetest.RouteAuth); calls != 1 {
+		t.Fatalf("auth calls = %d, want 1", calls)
+	}
+}
+
+func TestMakePayment(t *testing.T) {
+	s, server := newService(t)
+	server.SetDefault(softlinetest.RouteMakePayment, softlinetest.Response{Body: softlinetest.PaymentCreated(43)})
+
+	_, response, err := s.MakePayment(context.Background(), softline.MakePaymentReq{
+		ParentOrderId: 42,
+		Currency:      "RUB",
+		Amount:        softline.NewAmount(10050, 2),
+	}, "")
+	if err != nil {
+		t.Fatalf("MakePayment: %v", err)
+	}
+	if response.OrderId != 43 {
+		t.Fatalf("MakePayment order = %d, want 43", response.OrderId)
+	}
+}
+
+func TestOrderOperations(t *testing.T) {
+	tests := []struct {
+		name   string
+		route  softlinetest.Route
+		status softline.PaymentStatus
+		call   func(s *softline.Service) (*softline.PaymentResp, error)
+	}{
+		{
+			name:   "PostCheck",
+			route:  softlinetest.RouteOrder,
+			status: softline.StatusPaid,
+			call: func(s *softline.Service) (*softline.PaymentResp, error) {
+				_, response, err := s.PostCheck(context.Background(), "42", "")
+				return response, err
+			},
+		},
+		{
+			name:   "Refund",
+			route:  softlinetest.RouteRefund,
+			status: softline.StatusRefunded,
+			call: func(s *softline.Service) (*softline.PaymentResp, error) {
+				return s.Refund(context.Background(), softline.RefundReq{OrderID: "42"}, "")
+			},
+		},
+		{
+			name:   "Capture",
+			route:  softlinetest.RouteCapture,
+			status: softline.StatusPaid,
+			call: func(s *softline.Service) (*softline.PaymentResp, error) {
+				_, response, err := s.Capture(context.Background(), softline.CaptureReq{OrderID: "42"}, "")
+				return response, err
+			},
+		},
+		{
+			name:   "Cancel",
+			route:  softlinetest.RouteCancel,
+			status: softline.StatusCanceled,
+			call: func(s *softline.Service) (*softline.PaymentResp, error) {
+				_, response, err := s.Cancel(context.Background(), softline.CancelReq{OrderID: "42"}, "")
+				return response, err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, server := newService(t)
+			server.SetDefault(tt.route, softlinetest.Response{Body: softlinetest.Order(42, tt.status)})
+
+			response, err := tt.call(s)
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			if response.OrderId != 42 || response.Status != tt.status {
+				t.Fatalf("%s = order %d, status %q; want 42, %q", tt.name, response.OrderId, response.Status, tt.status)
+			}
+
+			var request *softlinetest.Request
+			requests := server.Requests()
+			for i := range requests {
+				if requests[i].Route == tt.route {
+					request = &requests[i]
+				}
+			}
+			if request == nil || request.OrderID != "42" {
+				t.Fatalf("server did not receive %s request for order 42: %+v", tt.route, request)
+			}
+		})
+	}
+}
+
+func TestErrorFixtures(t *testing.T) {
+	tests := []struct {
+		name     string
+		response softlinetest.Response
+		want     error
+	}{
+		{name: "NotFound", response: softlinetest.NotFound(), want: softline.ErrNotFound},
+		{name: "ValidationError", response: softlinetest.ValidationError("amount is invalid"), want: softline.ErrValidation},
+		{name: "ServerError", response: softlinetest.ServerError(), want: softline.ErrServer},
+		{name: "Unavailable", response: softlinetest.Unavailable(), want: softline.ErrServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, server := newService(t)
+			server.Enqueue(softlinetest.RouteOrder, tt.response)
+
+			_, _, err := s.PostCheck(context.Background(), "42", "")
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("PostCheck error = %v, want %v", err, tt.want)
+			}
+
+			var apiErr *softline.APIError
+			if !errors.As(err, &apiErr) || apiErr.HTTPStatus != tt.response.Status {
+				t.Fatalf("PostCheck error = %v, want APIError with status %d", err, tt.response.Status)
+			}
+		})
+	}
+}
+
+func TestAuthUnauthorized(t *testing.T) {
+	s, server := newService(t)
+	server.Enqueue(softlinetest.RouteAuth, softlinetest.Unauthorized())
+
+	if _, err := s.Auth(context.Background()); !errors.Is(err, softline.ErrUnauthorized) {
+		t.Fatalf("Auth error = %v, want %v", err, softline.ErrUnauthorized)
+	}
+}
+
+func TestExpiredTokenReauth(t *testing.T) {
+	s, server := newService(t)
+	server.Enqueue(softlinetest.RouteOrder, softlinetest.Unauthorized())
+
+	// на 401 Service сбрасывает токен, авторизуется заново и повторяет запрос
+	if _, _, err := s.PostCheck(context.Background(), "42", ""); err != nil {
+		t.Fatalf("PostCheck: %v", err)
+	}
+	if calls := server.Calls(softlinetest.RouteAuth); calls != 2 {
+		t.Fatalf("auth calls = %d, want 2", calls)
+	}
+	if calls := server.Calls(softlinetest.RouteOrder); calls != 2 {
+		t.Fatalf("order calls = %d, want 2", calls)
+	}
+}
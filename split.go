@@ -0,0 +1,51 @@
+package softlinePayment
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Split — доля платежа, которая при расчётах уходит субмерчанту маркетплейса.
+// Commission — комиссия площадки, удерживаемая из этой доли.
+type Split struct {
+	SubMerchantId string  `json:"sub_merchant_id"`
+	Amount        Amount  `json:"amount"`
+	Commission    *Amount `json:"commission_amount,omitempty"`
+	Description   string  `json:"description,omitempty"`
+}
+
+// validateSplits проверяет, что доли в сумме дают сумму платежа, а комиссии не превышают долей.
+func validateSplits(splits []Split, commission *Amount, total Amount) error {
+	var errs []error
+
+	if commission != nil && (commission.IsNegative() || commission.Cmp(total) > 0) {
+		errs = append(errs, fmt.Errorf("commission %s must be between 0 and payment amount %s", commission, total))
+	}
+
+	var sum Amount
+	for i, split := range splits {
+		if split.SubMerchantId == "" {
+			errs = append(errs, fmt.Errorf("split %d: sub_merchant_id is required", i))
+		}
+		if split.Amount.IsZero() || split.Amount.IsNegative() {
+			errs = append(errs, fmt.Errorf("split %d: amount must be positive", i))
+		}
+		if split.Commission != nil && (split.Commission.IsNegative() || split.Commission.Cmp(split.Amount) > 0) {
+			errs = append(errs, fmt.Errorf("split %d: commission %s must be between 0 and split amount %s", i, split.Commission, split.Amount))
+		}
+
+		var err error
+		if sum, err = sum.Add(split.Amount); err != nil {
+			errs = append(errs, fmt.Errorf("split %d: %w", i, err))
+		}
+	}
+
+	if len(splits) > 0 && sum.Cmp(total) != 0 {
+		errs = append(errs, fmt.Errorf("splits total %s does not match payment amount %s", sum, total))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrValidation, errors.Join(errs...))
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package softlinePayment
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrorClass — класс ошибки вызова SOM в Stats.
+type ErrorClass string
+
+const (
+	ErrorClassValidation   ErrorClass = "validation"
+	ErrorClassUnauthorized ErrorClass = "unauthorized"
+	ErrorClassNotFound     ErrorClass = "not_found"
+	ErrorClassRejected     ErrorClass = "rejected"
+	ErrorClassRateLimited  ErrorClass = "rate_limited"
+	ErrorClassServer       ErrorClass = "server"
+	ErrorClassCircuitOpen  ErrorClass = "circuit_open"
+	ErrorClassTimeout      ErrorClass = "timeout"
+	ErrorClassCanceled     ErrorClass = "canceled"
+	ErrorClassOther        ErrorClass = "other" // сеть, разбор ответа и прочее
+)
+
+var errorClasses = []ErrorClass{
+	ErrorClassValidation,
+	ErrorClassUnauthorized,
+	ErrorClassNotFound,
+	ErrorClassRejected,
+	ErrorClassRateLimited,
+	ErrorClassServer,
+	ErrorClassCircuitOpen,
+	ErrorClassTimeout,
+	ErrorClassCanceled,
+	ErrorClassOther,
+}
+
+func classifyError(err error) ErrorClass {
+	switch {
+	case errors.Is(err, ErrValidation):
+		return ErrorClassValidation
+	case errors.Is(err, ErrUnauthorized):
+		return ErrorClassUnauthorized
+	case errors.Is(err, ErrNotFound):
+		return ErrorClassNotFound
+	case errors.Is(err, ErrRejected):
+		return ErrorClassRejected
+	case errors.Is(err, ErrRateLimited):
+		return ErrorClassRateLimited
+	case errors.Is(err, ErrServer):
+		return ErrorClassServer
+	case errors.Is(err, ErrCircuitOpen):
+		return ErrorClassCircuitOpen
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	}
+	return ErrorClassOther
+}
+
+// Stats — снимок счётчиков клиента с момента создания Service.
+type Stats struct {
+	Requests           int64
+	Errors             map[ErrorClass]int64
+	Retries            int64
+	TokenRefreshes     int64
+	TokenRefreshErrors int64
+	AverageLatency     time.Duration
+}
+
+// serviceStats накапливает счётчики атомарно; читается через Service.Stats.
+type serviceStats struct {
+	requests           atomic.Int64
+	errors             map[ErrorClass]*atomic.Int64 // заполняется в newServiceStats и не меняется
+	retries            atomic.Int64
+	tokenRefreshes     atomic.Int64
+	tokenRefreshErrors atomic.Int64
+	latencyNanos       atomic.Int64
+}
+
+// Stats возвращает счётчики запросов, ошибок по классам, повторов и авторизаций.
+// Безопасен для вызова из любой горутины, например из health-эндпоинта.
+func (s *Service) Stats() Stats {
+	stats := Stats{
+		Requests:           s.stats.requests.Load(),
+		Errors:             make(map[ErrorClass]int64),
+		Retries:            s.stats.retries.Load(),
+		TokenRefreshes:     s.stats.tokenRefreshes.Load(),
+		TokenRefreshErrors: s.stats.tokenRefreshErrors.Load(),
+	}
+	for class, counter := range s.stats.errors {
+		if n := counter.Load(); n > 0 {
+			stats.Errors[class] = n
+		}
+	}
+	if stats.Requests > 0 {
+		stats.AverageLatency = time.Duration(s.stats.latencyNanos.Load() / stats.Requests)
+	}
+	return stats
+}
+
+func newServiceStats() *serviceStats {
+	st := &serviceStats{errors: make(map[ErrorClass]*atomic.Int64, len(errorClasses))}
+	for _, class := range errorClasses {
+		st.errors[class] = new(atomic.Int64)
+	}
+	return st
+}
+
+func (st *serviceStats) requestDone(duration time.Duration, err error) {
+	st.requests.Add(1)
+	st.latencyNanos.Add(int64(duration))
+	if err == nil {
+		return
+	}
+
+	st.errors[classifyError(err)].Add(1)
+}
+
+func (st *serviceStats) retried() {
+	st.retries.Add(1)
+}
+
+func (st *serviceStats) tokenRefreshed(err error) {
+	st.tokenRefreshes.Add(1)
+	if err != nil {
+		st.tokenRefreshErrors.Add(1)
+	}
+}
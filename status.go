@@ -0,0 +1,60 @@
+package softlinePayment
+
+type PaymentStatus string
+
+const (
+	StatusNew               PaymentStatus = "new"
+	StatusPending           PaymentStatus = "pending"
+	StatusAwaiting3DS       PaymentStatus = "awaiting_3ds"
+	StatusAuthorized        PaymentStatus = "authorized"
+	StatusPaid              PaymentStatus = "paid"
+	StatusDeclined          PaymentStatus = "declined"
+	StatusCanceled          PaymentStatus = "canceled"
+	StatusExpired           PaymentStatus = "expired"
+	StatusError             PaymentStatus = "error"
+	StatusPartiallyRefunded PaymentStatus = "partially_refunded"
+	StatusRefunded          PaymentStatus = "refunded"
+	StatusChargeback        PaymentStatus = "chargeback"
+)
+
+// IsTerminal — статус окончательный и больше не изменится без действий мерчанта.
+func (s PaymentStatus) IsTerminal() bool {
+	switch s {
+	case StatusPaid, StatusDeclined, StatusCanceled, StatusExpired, StatusError,
+		StatusPartiallyRefunded, StatusRefunded, StatusChargeback:
+		return true
+	}
+	return false
+}
+
+// IsSuccessful — деньги списаны с покупателя.
+func (s PaymentStatus) IsSuccessful() bool {
+	switch s {
+	case StatusPaid, StatusPartiallyRefunded:
+		return true
+	}
+	return false
+}
+
+// IsRefundable — по заказу ещё можно сделать возврат.
+func (s PaymentStatus) IsRefundable() bool {
+	switch s {
+	case StatusPaid, StatusPartiallyRefunded:
+		return true
+	}
+	return false
+}
+
+// IsKnown — статус входит в список известных SDK.
+func (s PaymentStatus) IsKnown() bool {
+	switch s {
+	case StatusNew, StatusPending, StatusAwaiting3DS, StatusAuthorized, StatusPaid, StatusDeclined,
+		StatusCanceled, StatusExpired, StatusError, StatusPartiallyRefunded, StatusRefunded, StatusChargeback:
+		return true
+	}
+	return false
+}
+
+func (s PaymentStatus) String() string {
+	return string(s)
+}
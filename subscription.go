@@ -0,0 +1,77 @@
+package softlinePayment
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	subscriptions      = "/v1/subscription"
+	subscription       = "/v1/subscription/%s"
+	cancelSubscription = "/v1/subscription/%s/cancel"
+)
+
+func (s *Service) CreateSubscription(ctx context.Context, data CreateSubscriptionReq, token string, opts ...RequestOption) (respBody []byte, response *SubscriptionResp, err error) {
+	return call[CreateSubscriptionReq, SubscriptionResp](ctx, s, &SendParams{
+		Operation:  "create_subscription",
+		Path:       subscriptions,
+		HttpMethod: http.MethodPost,
+		AuthNeed:   true,
+	}, &data, token, opts)
+}
+
+func (s *Service) GetSubscription(ctx context.Context, subscriptionID string, token string, opts ...RequestOption) (respBody []byte, response *SubscriptionResp, err error) {
+	path, err := endpointPath(subscription, subscriptionID)
+	if err != nil {
+		return nil, new(SubscriptionResp), err
+	}
+
+	return call[struct{}, SubscriptionResp](ctx, s, &SendParams{
+		Operation:  "get_subscription",
+		Path:       path,
+		HttpMethod: http.MethodGet,
+		AuthNeed:   true,
+	}, nil, token, opts)
+}
+
+func (s *Service) UpdateSubscription(ctx context.Context, data UpdateSubscriptionReq, token string, opts ...RequestOption) (respBody []byte, response *SubscriptionResp, err error) {
+	path, err := endpointPath(subscription, data.SubscriptionID)
+	if err != nil {
+		return nil, new(SubscriptionResp), err
+	}
+
+	return call[UpdateSubscriptionReq, SubscriptionResp](ctx, s, &SendParams{
+		Operation:  "update_subscription",
+		Path:       path,
+		HttpMethod: http.MethodPatch,
+		AuthNeed:   true,
+	}, &data, token, opts)
+}
+
+func (s *Service) PauseSubscription(ctx context.Context, subscriptionID string, token string, opts ...RequestOption) ([]byte, *SubscriptionResp, error) {
+	return s.UpdateSubscription(ctx, UpdateSubscriptionReq{
+		SubscriptionID: subscriptionID,
+		Status:         SubscriptionPaused,
+	}, token, opts...)
+}
+
+func (s *Service) ResumeSubscription(ctx context.Context, subscriptionID string, token string, opts ...RequestOption) ([]byte, *SubscriptionResp, error) {
+	return s.UpdateSubscription(ctx, UpdateSubscriptionReq{
+		SubscriptionID: subscriptionID,
+		Status:         SubscriptionActive,
+	}, token, opts...)
+}
+
+func (s *Service) CancelSubscription(ctx context.Context, subscriptionID string, token string, opts ...RequestOption) (respBody []byte, response *SubscriptionResp, err error) {
+	path, err := endpointPath(cancelSubscription, subscriptionID)
+	if err != nil {
+		return nil, new(SubscriptionResp), err
+	}
+
+	return call[struct{}, SubscriptionResp](ctx, s, &SendParams{
+		Operation:  "cancel_subscription",
+		Path:       path,
+		HttpMethod: http.MethodPost,
+		AuthNeed:   true,
+	}, nil, token, opts)
+}
@@ -0,0 +1,90 @@
+package softlinePayment
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/dwnGnL/softlinePayment"
+
+type telemetry struct {
+	tracer   trace.Tracer
+	requests metric.Int64Counter
+	errors   metric.Int64Counter
+	latency  metric.Float64Histogram
+}
+
+// WithTracerProvider задаёт провайдер трейсов OpenTelemetry. По умолчанию используется глобальный.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(s *Service) {
+		s.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider задаёт провайдер метрик OpenTelemetry. По умолчанию используется глобальный.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(s *Service) {
+		s.meterProvider = provider
+	}
+}
+
+func newTelemetry(tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) *telemetry {
+	if tracerProvider == nil {
+		tracerProvider = otel.GetTracerProvider()
+	}
+	if meterProvider == nil {
+		meterProvider = otel.GetMeterProvider()
+	}
+
+	meter := meterProvider.Meter(instrumentationName)
+	t := &telemetry{
+		tracer: tracerProvider.Tracer(instrumentationName),
+	}
+
+	// ошибки регистрации инструментов не критичны: метер возвращает рабочие no-op инструменты
+	t.requests, _ = meter.Int64Counter("softline.requests",
+		metric.WithDescription("Number of requests sent to SOM"))
+	t.errors, _ = meter.Int64Counter("softline.errors",
+		metric.WithDescription("Number of failed requests to SOM"))
+	t.latency, _ = meter.Float64Histogram("softline.request.duration",
+		metric.WithDescription("Duration of requests to SOM"),
+		metric.WithUnit("s"))
+
+	return t
+}
+
+// start открывает span запроса и возвращает функцию, завершающую его и записывающую метрики.
+func (t *telemetry) start(ctx context.Context, inputs *SendParams) (context.Context, func(err error)) {
+	started := time.Now()
+	ctx, span := t.tracer.Start(ctx, "softline."+inputs.Operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("softline.operation", inputs.Operation),
+			attribute.String("http.method", inputs.HttpMethod),
+		),
+	)
+
+	return ctx, func(err error) {
+		attrs := []attribute.KeyValue{
+			attribute.String("operation", inputs.Operation),
+			attribute.Int("http.status_code", inputs.HttpCode),
+		}
+
+		span.SetAttributes(attribute.Int("http.status_code", inputs.HttpCode))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			t.errors.Add(ctx, 1, metric.WithAttributes(attrs...))
+		}
+		span.End()
+
+		t.requests.Add(ctx, 1, metric.WithAttributes(attrs...))
+		t.latency.Record(ctx, time.Since(started).Seconds(), metric.WithAttributes(attrs...))
+	}
+}
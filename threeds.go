@@ -0,0 +1,175 @@
+package softlinePayment
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const completeThreeDS = "/v1/order/%s/3ds"
+
+// ThreeDSChallenge — данные для перенаправления покупателя на страницу ACS банка.
+// Для 3DS 1.0 заполняются PaReq и MD, для 3DS 2.x — Creq и SessionData.
+type ThreeDSChallenge struct {
+	Version     string `json:"version"`
+	AcsUrl      string `json:"acs_url"`
+	PaReq       string `json:"pareq,omitempty"`
+	MD          string `json:"md,omitempty"`
+	Creq        string `json:"creq,omitempty"`
+	SessionData string `json:"three_ds_session_data,omitempty"`
+	TermUrl     string `json:"term_url,omitempty"`
+}
+
+// FormFields возвращает поля POST-формы для отправки на AcsUrl.
+func (c *ThreeDSChallenge) FormFields() map[string]string {
+	fields := make(map[string]string)
+	if c.Creq != "" {
+		fields["creq"] = c.Creq
+		if c.SessionData != "" {
+			fields["threeDSSessionData"] = c.SessionData
+		}
+		return fields
+	}
+
+	fields["PaReq"] = c.PaReq
+	fields["MD"] = c.MD
+	if c.TermUrl != "" {
+		fields["TermUrl"] = c.TermUrl
+	}
+	return fields
+}
+
+var threeDSFormTemplate = template.Must(template.New("3ds").Parse(`<!DOCTYPE html>
+<html>
+<body onload="document.forms[0].submit()">
+<form method="POST" action="{{.Action}}">
+{{- range $name, $value := .Fields}}
+<input type="hidden" name="{{$name}}" value="{{$value}}">
+{{- end}}
+<noscript><button type="submit">Continue</button></noscript>
+</form>
+</body>
+</html>
+`))
+
+// RedirectForm возвращает HTML-страницу, которая автоматически отправляет покупателя на ACS.
+func (c *ThreeDSChallenge) RedirectForm() (string, error) {
+	buf := new(bytes.Buffer)
+	err := threeDSFormTemplate.Execute(buf, struct {
+		Action string
+		Fields map[string]string
+	}{
+		Action: c.AcsUrl,
+		Fields: c.FormFields(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("can't render 3ds form: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// CompleteThreeDSReq — ответ ACS, пришедший на TermUrl.
+type CompleteThreeDSReq struct {
+	OrderID string `json:"-"`
+	PaRes   string `json:"pares,omitempty"`
+	MD      string `json:"md,omitempty"`
+	Cres    string `json:"cres,omitempty"`
+}
+
+// ThreeDSResultFromRequest извлекает результат 3DS из POST-запроса ACS на TermUrl.
+func ThreeDSResultFromRequest(r *http.Request, orderID string) (CompleteThreeDSReq, error) {
+	if err := r.ParseForm(); err != nil {
+		return CompleteThreeDSReq{}, fmt.Errorf("can't parse 3ds callback form: %w", err)
+	}
+
+	return CompleteThreeDSReq{
+		OrderID: orderID,
+		PaRes:   r.PostForm.Get("PaRes"),
+		MD:      r.PostForm.Get("MD"),
+		Cres:    r.PostForm.Get("cres"),
+	}, nil
+}
+
+func (s *Service) CompleteThreeDS(ctx context.Context, request CompleteThreeDSReq, token string, opts ...RequestOption) (respBody []byte, response *PaymentResp, err error) {
+	if err = validateOrderID(request.OrderID); err != nil {
+		return nil, new(PaymentResp), err
+	}
+	path, err := endpointPath(completeThreeDS, request.OrderID)
+	if err != nil {
+		return nil, new(PaymentResp), err
+	}
+
+	return call[CompleteThreeDSReq, PaymentResp](ctx, s, &SendParams{
+		Operation:  "complete_3ds",
+		Path:       path,
+		HttpMethod: http.MethodPost,
+		AuthNeed:   true,
+	}, &request, token, opts)
+}
+
+const defaultThreeDSTimeout = 15 * time.Minute
+
+var ErrThreeDSTimeout = errors.New("softline: 3ds was not completed in time")
+
+// WaitForThreeDS дожидается результата 3DS по заказу: статуса, отличного от new, pending
+// и awaiting_3ds. Результат приходит из колбэка через NotifyPaymentStatus или опросом PostCheck;
+// интервал опроса не выходит за дедлайн ctx. Без дедлайна ждёт не дольше 15 минут.
+// По истечении времени возвращает последний ответ и ErrThreeDSTimeout.
+func (s *Service) WaitForThreeDS(ctx context.Context, orderID string, opts PollOptions) (*PaymentResp, error) {
+	if opts.Interval <= 0 {
+		opts = DefaultPollOptions()
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultThreeDSTimeout)
+		defer cancel()
+	}
+
+	// колбэк будит ожидание раньше очередного опроса
+	notify := make(chan *PaymentResp, 1)
+	if id, err := strconv.ParseInt(orderID, 10, 64); err == nil {
+		waiter := &PendingPayment{notify: notify}
+		s.waiters.add(id, waiter)
+		defer s.waiters.remove(id, waiter)
+	}
+
+	var last *PaymentResp
+	interval := opts.Interval
+	for {
+		_, response, err := s.PostCheck(ctx, orderID, "", WithoutCache())
+		if err != nil && ctx.Err() == nil {
+			return last, err
+		}
+		if err == nil {
+			last = response
+			if threeDSCompleted(response.Status) {
+				return response, nil
+			}
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case payment := <-notify:
+			timer.Stop()
+			return payment, nil
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return last, fmt.Errorf("%w: order %s: %w", ErrThreeDSTimeout, orderID, ctx.Err())
+		}
+		interval = opts.next(interval)
+	}
+}
+
+func threeDSCompleted(status PaymentStatus) bool {
+	switch status {
+	case StatusNew, StatusPending, StatusAwaiting3DS:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+package softlinePayment
+
+import "time"
+
+// Классы операций для Config.OperationTimeoutSec. Ключом может быть и имя отдельной
+// операции (например, "post_check"), оно важнее класса.
+const (
+	TimeoutClassAuth    = "auth"
+	TimeoutClassPayment = "payment"
+	TimeoutClassStatus  = "status"
+	TimeoutClassRefund  = "refund"
+)
+
+var operationClasses = map[string]string{
+	"auth":             TimeoutClassAuth,
+	"create_payment":   TimeoutClassPayment,
+	"make_payment":     TimeoutClassPayment,
+	"pay_with_card":    TimeoutClassPayment,
+	"complete_3ds":     TimeoutClassPayment,
+	"capture":          TimeoutClassPayment,
+	"cancel":           TimeoutClassPayment,
+	"post_check":       TimeoutClassStatus,
+	"get_refund":       TimeoutClassStatus,
+	"get_payout":       TimeoutClassStatus,
+	"get_subscription": TimeoutClassStatus,
+	"search_orders":    TimeoutClassStatus,
+	"lookup_bin":       TimeoutClassStatus,
+	"refund":           TimeoutClassRefund,
+	"refund_partial":   TimeoutClassRefund,
+}
+
+// operationTimeout — ограничение одной попытки запроса для операции; 0 — действует только RequestTimeoutSec.
+func (c *Config) operationTimeout(operation string) time.Duration {
+	seconds, ok := c.OperationTimeoutSec[operation]
+	if !ok {
+		seconds = c.OperationTimeoutSec[operationClasses[operation]]
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,70 @@
+package softlinePayment
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TLSConfig — настройки TLS соединения с SOM. Сертификаты задаются PEM-файлами
+// или готовыми значениями; файлы читаются один раз в New.
+type TLSConfig struct {
+	// дополнительные корневые сертификаты; без них используются системные
+	RootCAFile string
+	RootCAs    *x509.CertPool `json:"-"`
+	// клиентский сертификат для mTLS
+	ClientCertFile string
+	ClientKeyFile  string
+	Certificates   []tls.Certificate `json:"-"`
+	// минимальная версия TLS, например tls.VersionTLS12; 0 — по умолчанию Go
+	MinVersion uint16
+	// имя сервера для SNI и проверки сертификата, если оно отличается от хоста в URI
+	ServerName string
+}
+
+func (c *TLSConfig) validate() error {
+	if (c.ClientCertFile == "") != (c.ClientKeyFile == "") {
+		return errors.New("TLS.ClientCertFile and TLS.ClientKeyFile must be set together")
+	}
+	if c.MinVersion != 0 && (c.MinVersion < tls.VersionTLS10 || c.MinVersion > tls.VersionTLS13) {
+		return fmt.Errorf("unsupported TLS.MinVersion %#x", c.MinVersion)
+	}
+	return nil
+}
+
+// build собирает *tls.Config, читая файлы сертификатов.
+func (c *TLSConfig) build() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:   c.MinVersion,
+		ServerName:   c.ServerName,
+		RootCAs:      c.RootCAs,
+		Certificates: c.Certificates,
+	}
+
+	if c.RootCAFile != "" {
+		pem, err := os.ReadFile(c.RootCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("can't read root CA file: %w", err)
+		}
+		if config.RootCAs == nil {
+			if config.RootCAs, err = x509.SystemCertPool(); err != nil {
+				config.RootCAs = x509.NewCertPool()
+			}
+		}
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", c.RootCAFile)
+		}
+	}
+
+	if c.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.ClientCertFile, c.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("can't load client certificate: %w", err)
+		}
+		config.Certificates = append(config.Certificates, cert)
+	}
+
+	return config, nil
+}
@@ -0,0 +1,217 @@
+package softlinePayment
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// срок жизни токена, если в нём нет claim exp
+	tokenFallbackTTL = 5 * time.Minute
+	// ограничение на одно обновление токена, которое не привязано к контексту вызывающего
+	tokenRefreshTimeout = 30 * time.Second
+)
+
+// TokenManager лениво получает JWT через Auth, кэширует его и обновляет до истечения срока.
+// Одновременные вызовы разделяют одно обращение к Auth. В окне refreshAhead вызывающие
+// получают текущий токен, а новый запрашивается в фоне.
+type TokenManager struct {
+	mu           sync.Mutex
+	auth         func(ctx context.Context) (*AuthResp, error)
+	store        TokenStore
+	storeKey     func(ctx context.Context) (string, error)
+	refreshAhead time.Duration
+	clockSkew    time.Duration
+	token        string
+	expiresAt    time.Time
+	inflight     *tokenRefresh
+}
+
+// tokenRefresh — выполняющееся обновление токена, которого ждут вызывающие.
+type tokenRefresh struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+func newTokenManager(auth func(ctx context.Context) (*AuthResp, error), store TokenStore, storeKey func(ctx context.Context) (string, error), refreshAhead, clockSkew time.Duration) *TokenManager {
+	return &TokenManager{
+		auth:         auth,
+		store:        store,
+		storeKey:     storeKey,
+		refreshAhead: refreshAhead,
+		clockSkew:    clockSkew,
+	}
+}
+
+// fresh — токен не требует обновления.
+func (m *TokenManager) fresh(now time.Time) bool {
+	return m.token != "" && now.Add(m.clockSkew+m.refreshAhead).Before(m.expiresAt)
+}
+
+// usable — токен ещё действителен с учётом расхождения часов, но пора обновлять.
+func (m *TokenManager) usable(now time.Time) bool {
+	return m.token != "" && now.Add(m.clockSkew).Before(m.expiresAt)
+}
+
+// Token возвращает действующий токен, при необходимости авторизуясь заново.
+func (m *TokenManager) Token(ctx context.Context) (string, error) {
+	m.mu.Lock()
+	now := time.Now()
+	if m.fresh(now) {
+		token := m.token
+		m.mu.Unlock()
+		return token, nil
+	}
+	if m.usable(now) {
+		token := m.token
+		m.refresh()
+		m.mu.Unlock()
+		return token, nil
+	}
+	refresh := m.refresh()
+	m.mu.Unlock()
+
+	select {
+	case <-refresh.done:
+		return refresh.token, refresh.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// refresh запускает обновление токена, если оно ещё не идёт. Вызывается под m.mu.
+func (m *TokenManager) refresh() *tokenRefresh {
+	if m.inflight != nil {
+		return m.inflight
+	}
+
+	refresh := &tokenRefresh{done: make(chan struct{})}
+	m.inflight = refresh
+
+	go func() {
+		// обновление общее для всех ожидающих, поэтому не зависит от контекста одного из них
+		ctx, cancel := context.WithTimeout(context.Background(), tokenRefreshTimeout)
+		defer cancel()
+
+		refresh.token, refresh.err = m.fetch(ctx)
+
+		m.mu.Lock()
+		m.inflight = nil
+		m.mu.Unlock()
+		close(refresh.done)
+	}()
+
+	return refresh
+}
+
+func (m *TokenManager) fetch(ctx context.Context) (string, error) {
+	// токен мог уже получить другой экземпляр сервиса
+	var storeKey string
+	if m.store != nil {
+		var err error
+		if storeKey, err = m.storeKey(ctx); err != nil {
+			return "", err
+		}
+		token, ok, err := m.store.Get(ctx, storeKey)
+		if err != nil {
+			return "", fmt.Errorf("can't get token from store: %w", err)
+		}
+		if ok {
+			m.mu.Lock()
+			m.setToken(token)
+			fresh := m.fresh(time.Now())
+			m.mu.Unlock()
+			if fresh {
+				return token, nil
+			}
+		}
+	}
+
+	resp, err := m.auth(ctx)
+	if err != nil {
+		return "", fmt.Errorf("can't refresh token: %w", err)
+	}
+
+	m.mu.Lock()
+	m.setToken(resp.Token)
+	expiresAt := m.expiresAt
+	m.mu.Unlock()
+
+	if m.store != nil {
+		if err = m.store.Set(ctx, storeKey, resp.Token, time.Until(expiresAt)); err != nil {
+			return "", fmt.Errorf("can't save token to store: %w", err)
+		}
+	}
+
+	return resp.Token, nil
+}
+
+// setToken вызывается под m.mu.
+func (m *TokenManager) setToken(token string) {
+	expiresAt, err := parseTokenExpiry(token)
+	if err != nil {
+		expiresAt = time.Now().Add(tokenFallbackTTL)
+	}
+
+	m.token = token
+	m.expiresAt = expiresAt
+}
+
+// ExpiresAt возвращает время истечения закэшированного токена.
+func (m *TokenManager) ExpiresAt() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.expiresAt
+}
+
+// Invalidate сбрасывает закэшированный токен, в том числе в TokenStore.
+func (m *TokenManager) Invalidate(ctx context.Context) error {
+	m.mu.Lock()
+	m.token = ""
+	m.expiresAt = time.Time{}
+	m.mu.Unlock()
+
+	if m.store != nil {
+		storeKey, err := m.storeKey(ctx)
+		if err != nil {
+			return err
+		}
+		if err = m.store.Delete(ctx, storeKey); err != nil {
+			return fmt.Errorf("can't delete token from store: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func parseTokenExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("malformed jwt: expected 3 parts, got %d", len(parts))
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("can't decode jwt payload: %w", err)
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("can't unmarshal jwt claims: %w", err)
+	}
+
+	if claims.Exp == 0 {
+		return time.Time{}, fmt.Errorf("jwt has no exp claim")
+	}
+
+	return time.Unix(claims.Exp, 0), nil
+}
@@ -0,0 +1,191 @@
+package softlinePayment
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testJWT возвращает JWT с exp через ttl; подпись не проверяется.
+func testJWT(ttl time.Duration, id int) string {
+	enc := base64.RawURLEncoding
+	payload := enc.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d,"jti":"%d"}`, time.Now().Add(ttl).Unix(), id)))
+	return enc.EncodeToString([]byte(`{"alg":"HS256"}`)) + "." + payload + ".signature"
+}
+
+// countingAuth выдаёт новый токен на каждый вызов; release, если задан, задерживает ответ.
+type countingAuth struct {
+	calls   atomic.Int32
+	ttl     time.Duration
+	err     error
+	release chan struct{}
+}
+
+func (a *countingAuth) auth(ctx context.Context) (*AuthResp, error) {
+	n := a.calls.Add(1)
+	if a.release != nil {
+		<-a.release
+	}
+	if a.err != nil {
+		return nil, a.err
+	}
+	return &AuthResp{Token: testJWT(a.ttl, int(n))}, nil
+}
+
+func staticKey(context.Context) (string, error) {
+	return "softline:token:test", nil
+}
+
+func TestTokenSingleFlight(t *testing.T) {
+	auth := &countingAuth{ttl: time.Hour, release: make(chan struct{})}
+	manager := newTokenManager(auth.auth, nil, staticKey, 0, 0)
+
+	const callers = 50
+	tokens := make([]string, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], errs[i] = manager.Token(context.Background())
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(auth.release)
+	wg.Wait()
+
+	if n := auth.calls.Load(); n != 1 {
+		t.Fatalf("auth calls = %d, want 1", n)
+	}
+	for i := range tokens {
+		if errs[i] != nil || tokens[i] != tokens[0] {
+			t.Fatalf("caller %d got %q, %v; want %q", i, tokens[i], errs[i], tokens[0])
+		}
+	}
+
+	if token, err := manager.Token(context.Background()); err != nil || token != tokens[0] || auth.calls.Load() != 1 {
+		t.Fatalf("cached Token = %q, %v after %d auth calls", token, err, auth.calls.Load())
+	}
+}
+
+func TestTokenRefreshAhead(t *testing.T) {
+	auth := &countingAuth{ttl: 30 * time.Second}
+	manager := newTokenManager(auth.auth, nil, staticKey, time.Minute, 0)
+
+	first, err := manager.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+
+	// токен ещё действует, но попал в окно refreshAhead: отдаётся сразу, обновление идёт в фоне
+	if token, err := manager.Token(context.Background()); err != nil || token != first {
+		t.Fatalf("Token in refresh window = %q, %v; want the current token", token, err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for auth.calls.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := auth.calls.Load(); n != 2 {
+		t.Fatalf("auth calls = %d, want a background refresh", n)
+	}
+}
+
+func TestTokenCallerCancelDoesNotCancelRefresh(t *testing.T) {
+	auth := &countingAuth{ttl: time.Hour, release: make(chan struct{})}
+	manager := newTokenManager(auth.auth, nil, staticKey, 0, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := manager.Token(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Token = %v, want %v", err, context.Canceled)
+	}
+
+	close(auth.release)
+	if _, err := manager.Token(context.Background()); err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if n := auth.calls.Load(); n != 1 {
+		t.Fatalf("auth calls = %d, want the abandoned refresh to be reused", n)
+	}
+}
+
+func TestTokenAuthError(t *testing.T) {
+	auth := &countingAuth{ttl: time.Hour, err: ErrUnauthorized}
+	manager := newTokenManager(auth.auth, nil, staticKey, 0, 0)
+
+	if _, err := manager.Token(context.Background()); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("Token = %v, want %v", err, ErrUnauthorized)
+	}
+
+	auth.err = nil
+	if _, err := manager.Token(context.Background()); err != nil {
+		t.Fatalf("Token after failed refresh: %v", err)
+	}
+	if n := auth.calls.Load(); n != 2 {
+		t.Fatalf("auth calls = %d, want 2", n)
+	}
+}
+
+func TestTokenStoreSharedBetweenManagers(t *testing.T) {
+	store := NewMemoryTokenStore()
+	auth := &countingAuth{ttl: time.Hour}
+
+	first := newTokenManager(auth.auth, store, staticKey, 0, 0)
+	second := newTokenManager(auth.auth, store, staticKey, 0, 0)
+
+	token, err := first.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if got, err := second.Token(context.Background()); err != nil || got != token {
+		t.Fatalf("second manager Token = %q, %v; want the stored token", got, err)
+	}
+	if n := auth.calls.Load(); n != 1 {
+		t.Fatalf("auth calls = %d, want 1", n)
+	}
+
+	if err = first.Invalidate(context.Background()); err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+	if _, ok, _ := store.Get(context.Background(), "softline:token:test"); ok {
+		t.Fatal("token is still in store after Invalidate")
+	}
+}
+
+func TestParseTokenExpiry(t *testing.T) {
+	exp, err := parseTokenExpiry(testJWT(time.Hour, 1))
+	if err != nil || time.Until(exp) < 59*time.Minute {
+		t.Fatalf("parseTokenExpiry = %s, %v", exp, err)
+	}
+
+	enc := base64.RawURLEncoding
+	for _, token := range []string{
+		"opaque-token",
+		"a.!!!.c",
+		"a." + enc.EncodeToString([]byte("not json")) + ".c",
+		"a." + enc.EncodeToString([]byte(`{"sub":"1"}`)) + ".c",
+	} {
+		if _, err := parseTokenExpiry(token); err == nil {
+			t.Errorf("parseTokenExpiry(%q) succeeded, want error", token)
+		}
+	}
+}
+
+func TestTokenWithoutExpiryUsesFallbackTTL(t *testing.T) {
+	manager := newTokenManager(func(context.Context) (*AuthResp, error) {
+		return &AuthResp{Token: "opaque-token"}, nil
+	}, nil, staticKey, 0, 0)
+
+	if _, err := manager.Token(context.Background()); err != nil {
+		t.Fatalf("Token: %v", err)
+	}
+	if ttl := time.Until(manager.ExpiresAt()); ttl <= 0 || ttl > tokenFallbackTTL {
+		t.Fatalf("expires in %s, want within %s", ttl, tokenFallbackTTL)
+	}
+}
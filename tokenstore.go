@@ -0,0 +1,150 @@
+package softlinePayment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TokenStore хранит JWT вне процесса, чтобы реплики сервиса использовали общий токен.
+type TokenStore interface {
+	Get(ctx context.Context, key string) (token string, ok bool, err error)
+	Set(ctx context.Context, key string, token string, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// WithTokenStore задаёт хранилище токенов.
+func WithTokenStore(store TokenStore) Option {
+	return func(s *Service) {
+		s.tokenStore = store
+	}
+}
+
+// tokenStoreKey возвращает ключ токена в TokenStore. Логин берётся из Config.Secrets, если он
+// там задан: иначе все мерчанты с учётными данными только в Secrets делили бы один ключ.
+func tokenStoreKey(config *Config) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		login := config.Login
+		if config.Secrets != nil {
+			credentials, err := config.Secrets.Credentials(ctx)
+			if err != nil {
+				return "", fmt.Errorf("softline: can't resolve credentials: %w", err)
+			}
+			if credentials.Login != "" {
+				login = credentials.Login
+			}
+		}
+		return "softline:token:" + login, nil
+	}
+}
+
+// MemoryTokenStore — TokenStore в памяти процесса.
+type MemoryTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]memoryToken
+}
+
+type memoryToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{
+		tokens: make(map[string]memoryToken),
+	}
+}
+
+func (m *MemoryTokenStore) Get(_ context.Context, key string) (string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.tokens[key]
+	if !ok {
+		return "", false, nil
+	}
+	if !item.expiresAt.IsZero() && time.Now().After(item.expiresAt) {
+		delete(m.tokens, key)
+		return "", false, nil
+	}
+	return item.token, true, nil
+}
+
+func (m *MemoryTokenStore) Set(_ context.Context, key string, token string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item := memoryToken{token: token}
+	if ttl > 0 {
+		item.expiresAt = time.Now().Add(ttl)
+	}
+	m.tokens[key] = item
+
+	return nil
+}
+
+func (m *MemoryTokenStore) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.tokens, key)
+
+	return nil
+}
+
+// ErrRedisNil — значение, которое RedisClient должен вернуть при отсутствии ключа.
+var ErrRedisNil = errors.New("softline: redis key not found")
+
+// RedisClient — минимальный набор команд Redis, нужный RedisTokenStore.
+// Для go-redis достаточно тонкого адаптера:
+//
+//	func (a adapter) Get(ctx context.Context, key string) (string, error) {
+//		v, err := a.rdb.Get(ctx, key).Result()
+//		if errors.Is(err, redis.Nil) {
+//			return "", softline.ErrRedisNil
+//		}
+//		return v, err
+//	}
+//	func (a adapter) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+//		return a.rdb.Set(ctx, key, value, ttl).Err()
+//	}
+//	func (a adapter) Del(ctx context.Context, key string) error {
+//		return a.rdb.Del(ctx, key).Err()
+//	}
+type RedisClient interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value string, ttl time.Duration) error
+	Del(ctx context.Context, key string) error
+}
+
+// RedisTokenStore — TokenStore поверх Redis.
+type RedisTokenStore struct {
+	client RedisClient
+}
+
+func NewRedisTokenStore(client RedisClient) *RedisTokenStore {
+	return &RedisTokenStore{
+		client: client,
+	}
+}
+
+func (r *RedisTokenStore) Get(ctx context.Context, key string) (string, bool, error) {
+	token, err := r.client.Get(ctx, key)
+	if errors.Is(err, ErrRedisNil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return token, true, nil
+}
+
+func (r *RedisTokenStore) Set(ctx context.Context, key string, token string, ttl time.Duration) error {
+	return r.client.Set(ctx, key, token, ttl)
+}
+
+func (r *RedisTokenStore) Delete(ctx context.Context, key string) error {
+	return r.client.Del(ctx, key)
+}
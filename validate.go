@@ -0,0 +1,141 @@
+package softlinePayment
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+)
+
+var (
+	phonePattern      = regexp.MustCompile(`^\+?[1-9][0-9]{6,14}$`)
+	postalCodePattern = regexp.MustCompile(`^[0-9A-Za-z][0-9A-Za-z -]{1,14}$`)
+)
+
+// Validate проверяет запрос до отправки в SOM и возвращает все найденные ошибки разом.
+// Пустая валюта допускается: её подставит SOM по настройкам мерчанта.
+func (r *CreatePaymentReq) Validate() error {
+	var errs []error
+
+	errs = appendValidation(errs, validatePaymentAmount(r.Amount, r.Currency))
+	errs = append(errs, validateCustomer(r.Customer)...)
+	if r.ReturnSuccessUrl != "" {
+		errs = appendValidation(errs, validateCallbackURL("return_success_url", r.ReturnSuccessUrl))
+	}
+	if r.Receipt != nil {
+		errs = appendValidation(errs, r.Receipt.Validate(r.Amount))
+	}
+	if r.Installments != nil {
+		errs = appendValidation(errs, validateInstallmentCount(r.Installments.Count))
+	}
+	if len(r.Splits) > 0 || r.Commission != nil {
+		errs = appendValidation(errs, validateSplits(r.Splits, r.Commission, r.Amount))
+	}
+	errs = appendValidation(errs, r.Metadata.Validate())
+	if r.WalletToken != nil {
+		errs = append(errs, r.WalletToken.validate()...)
+	}
+	if r.BillingAddress != nil {
+		errs = append(errs, r.BillingAddress.validate()...)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrValidation, errors.Join(errs...))
+	}
+	return nil
+}
+
+// Validate проверяет запрос рекуррентного списания до отправки в SOM.
+func (r *MakePaymentReq) Validate() error {
+	var errs []error
+
+	if r.ParentOrderId <= 0 {
+		errs = append(errs, errors.New("parent_order_id is required"))
+	}
+	errs = appendValidation(errs, validatePaymentAmount(r.Amount, r.Currency))
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrValidation, errors.Join(errs...))
+	}
+	return nil
+}
+
+// Validate проверяет запрос полного возврата до отправки в SOM.
+func (r *RefundReq) Validate() error {
+	var errs []error
+
+	if r.OrderID == "" {
+		errs = append(errs, errors.New("order id is required"))
+	}
+	if r.Email != "" {
+		errs = appendValidation(errs, validateEmail("email", r.Email))
+	}
+	errs = append(errs, validateRefundDetails(r.ReasonCode, r.Initiator, r.Correction)...)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrValidation, errors.Join(errs...))
+	}
+	return nil
+}
+
+// validateOrderID проверяет идентификатор заказа, который подставляется в путь запроса.
+func validateOrderID(orderID string) error {
+	if orderID == "" {
+		return fmt.Errorf("%w: order id is required", ErrValidation)
+	}
+	return nil
+}
+
+func validateCustomer(customer Customer) (errs []error) {
+	if customer.Email != "" {
+		errs = appendValidation(errs, validateEmail("customer.email", customer.Email))
+	}
+	if customer.Phone != "" && !phonePattern.MatchString(customer.Phone) {
+		errs = append(errs, fmt.Errorf("customer.phone must be in international format, got %q", customer.Phone))
+	}
+	return errs
+}
+
+func (a *BillingAddress) validate() (errs []error) {
+	if !isCountryCode(a.Country) {
+		errs = append(errs, fmt.Errorf("billing_address.country must be an ISO 3166-1 alpha-2 code, got %q", a.Country))
+	}
+	if a.City == "" {
+		errs = append(errs, errors.New("billing_address.city is required"))
+	}
+	if !postalCodePattern.MatchString(a.PostalCode) {
+		errs = append(errs, fmt.Errorf("billing_address.postal_code is not valid: %q", a.PostalCode))
+	}
+	return errs
+}
+
+func validateEmail(field, email string) error {
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return fmt.Errorf("%s is not a valid email address: %q", field, email)
+	}
+	return nil
+}
+
+func validateCallbackURL(field, uri string) error {
+	u, err := url.Parse(uri)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s must be an absolute http(s) URL, got %q", field, uri)
+	}
+	return nil
+}
+
+// appendValidation добавляет ошибку в список, снимая обёртку ErrValidation,
+// чтобы она не повторялась в общем сообщении.
+func appendValidation(errs []error, err error) []error {
+	if err == nil {
+		return errs
+	}
+	if wrapped, ok := err.(interface{ Unwrap() []error }); ok {
+		if parts := wrapped.Unwrap(); len(parts) == 2 && parts[0] == ErrValidation {
+			err = parts[1]
+		}
+	}
+	return append(errs, err)
+}
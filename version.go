@@ -0,0 +1,6 @@
+package softlinePayment
+
+// Version — версия SDK, отправляется в заголовках User-Agent и X-SDK-Version.
+const Version = "1.0.0"
+
+const sdkVersionHeader = "X-SDK-Version"
@@ -0,0 +1,95 @@
+package softlinePayment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const applePaySession = "/v1/wallet/apple-pay/session"
+
+type WalletType string
+
+const (
+	WalletApplePay  WalletType = "apple_pay"
+	WalletGooglePay WalletType = "google_pay"
+)
+
+func (w WalletType) IsValid() bool {
+	switch w {
+	case WalletApplePay, WalletGooglePay:
+		return true
+	}
+	return false
+}
+
+// WalletToken — платёжный токен Apple Pay или Google Pay, полученный на клиенте.
+// PaymentData передаётся как есть: paymentData из PKPaymentToken для Apple Pay
+// или paymentMethodData.tokenizationData.token для Google Pay.
+type WalletToken struct {
+	Type          WalletType `json:"type"`
+	PaymentData   string     `json:"payment_data"`
+	Network       string     `json:"network,omitempty"`
+	DisplayName   string     `json:"display_name,omitempty"`
+	TransactionId string     `json:"transaction_id,omitempty"`
+}
+
+func (t *WalletToken) validate() []error {
+	var errs []error
+	if !t.Type.IsValid() {
+		errs = append(errs, fmt.Errorf("unknown wallet type %q", t.Type))
+	}
+	if t.PaymentData == "" {
+		errs = append(errs, errors.New("wallet payment_data is required"))
+	}
+	return errs
+}
+
+type ApplePaySessionReq struct {
+	// validationURL из события onvalidatemerchant на странице оплаты
+	ValidationURL string `json:"validation_url"`
+	DomainName    string `json:"domain_name"`
+	DisplayName   string `json:"display_name"`
+}
+
+// ApplePaySession — сессия мерчанта, которую страница передаёт в completeMerchantValidation без изменений.
+type ApplePaySession struct {
+	ResponseMeta `json:"-"`
+
+	MerchantSession json.RawMessage `json:"merchant_session"`
+	Errors          []Error         `json:"errors,omitempty"`
+}
+
+// ValidateApplePaySession запрашивает через SOM сессию мерчанта Apple Pay.
+// ValidationURL должен указывать на домен apple.com, иначе запрос отклоняется до отправки.
+func (s *Service) ValidateApplePaySession(ctx context.Context, request ApplePaySessionReq, token string, opts ...RequestOption) (respBody []byte, response *ApplePaySession, err error) {
+	if err = validateApplePayURL(request.ValidationURL); err != nil {
+		return nil, new(ApplePaySession), err
+	}
+	if request.DomainName == "" {
+		return nil, new(ApplePaySession), fmt.Errorf("%w: domain_name is required", ErrValidation)
+	}
+
+	return call[ApplePaySessionReq, ApplePaySession](ctx, s, &SendParams{
+		Operation:  "validate_apple_pay_session",
+		Path:       applePaySession,
+		HttpMethod: http.MethodPost,
+		AuthNeed:   true,
+	}, &request, token, opts)
+}
+
+func validateApplePayURL(uri string) error {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "https" {
+		return fmt.Errorf("%w: validation_url must be an https URL, got %q", ErrValidation, uri)
+	}
+	host := strings.ToLower(u.Hostname())
+	if host != "apple.com" && !strings.HasSuffix(host, ".apple.com") {
+		return fmt.Errorf("%w: validation_url must point to apple.com, got %q", ErrValidation, host)
+	}
+	return nil
+}
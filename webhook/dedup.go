@@ -0,0 +1,73 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	softline "github.com/dwnGnL/softlinePayment"
+)
+
+const defaultDedupTTL = 72 * time.Hour
+
+// DeduplicationStore атомарно резервирует идентификатор события, чтобы повторная
+// доставка того же колбэка, в том числе параллельная, не обрабатывалась дважды.
+type DeduplicationStore interface {
+	// Reserve возвращает true, если событие ещё не обрабатывалось и теперь зарезервировано.
+	Reserve(ctx context.Context, eventID string, ttl time.Duration) (bool, error)
+	// Release снимает резерв, если обработка не удалась и SOM должен доставить событие снова.
+	Release(ctx context.Context, eventID string) error
+}
+
+// EventID — идентификатор события для дедупликации: тип события, заказ и время события.
+func EventID(payment *softline.PaymentResp) string {
+	return fmt.Sprintf("%s:%d:%s", payment.Event, payment.OrderId, payment.EventDate.UTC().Format(time.RFC3339Nano))
+}
+
+// MemoryDeduplicationStore — DeduplicationStore в памяти процесса.
+type MemoryDeduplicationStore struct {
+	mu     sync.Mutex
+	events map[string]time.Time
+}
+
+func NewMemoryDeduplicationStore() *MemoryDeduplicationStore {
+	return &MemoryDeduplicationStore{
+		events: make(map[string]time.Time),
+	}
+}
+
+func (m *MemoryDeduplicationStore) Reserve(_ context.Context, eventID string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if expiresAt, ok := m.events[eventID]; ok && now.Before(expiresAt) {
+		return false, nil
+	}
+
+	for key, expiresAt := range m.events {
+		if now.After(expiresAt) {
+			delete(m.events, key)
+		}
+	}
+	m.events[eventID] = now.Add(ttl)
+
+	return true, nil
+}
+
+func (m *MemoryDeduplicationStore) Release(_ context.Context, eventID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.events, eventID)
+
+	return nil
+}
+
+func (h *Handler) dedupTTL() time.Duration {
+	if h.DedupTTL > 0 {
+		return h.DedupTTL
+	}
+	return defaultDedupTTL
+}
@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	softline "github.com/dwnGnL/softlinePayment"
+)
+
+const (
+	EventPaymentCreated    = "payment.created"
+	EventRefundCreated     = "refund.created"
+	EventRefundFailed      = "refund.failed"
+	EventChargebackCreated = "chargeback.created"
+	EventChargebackWon     = "chargeback.won"
+	EventChargebackLost    = "chargeback.lost"
+)
+
+// Event — разобранный колбэк SOM: *PaymentEvent, *RefundEvent или *ChargebackEvent.
+type Event interface {
+	EventType() string
+	EventOrderID() int64
+}
+
+// EventHeader — поля, общие для всех колбэков.
+type EventHeader struct {
+	Event      string            `json:"event"`
+	EventDate  time.Time         `json:"event_date"`
+	OrderId    int64             `json:"order_id"`
+	CreateDate time.Time         `json:"create_date"`
+	Metadata   softline.Metadata `json:"metadata,omitempty"`
+}
+
+func (h EventHeader) EventType() string {
+	return h.Event
+}
+
+func (h EventHeader) EventOrderID() int64 {
+	return h.OrderId
+}
+
+// PaymentEvent — payment.created, payment.succeeded, payment.failed.
+type PaymentEvent struct {
+	softline.PaymentResp
+}
+
+func (e *PaymentEvent) EventType() string {
+	return e.Event
+}
+
+func (e *PaymentEvent) EventOrderID() int64 {
+	return e.OrderId
+}
+
+// RefundEvent — refund.created, refund.completed, refund.failed.
+type RefundEvent struct {
+	EventHeader
+	RefundId         string          `json:"refund_id"`
+	Amount           softline.Amount `json:"amount"`
+	Currency         string          `json:"currency"`
+	Status           string          `json:"status"`
+	Reason           string          `json:"reason"`
+	ErrorCode        string          `json:"error_code"`
+	ErrorDescription string          `json:"error_description"`
+}
+
+// ChargebackEvent — chargeback.created, chargeback.won, chargeback.lost.
+type ChargebackEvent struct {
+	EventHeader
+	ChargebackId    string          `json:"chargeback_id"`
+	Amount          softline.Amount `json:"amount"`
+	Currency        string          `json:"currency"`
+	Status          string          `json:"status"`
+	Reason          string          `json:"reason"`
+	ReasonCode      string          `json:"reason_code"`
+	DisputeId       string          `json:"dispute_id"`                  // доказательства загружаются через Service.UploadEvidence
+	EvidenceDueDate *time.Time      `json:"evidence_due_date,omitempty"` // nil — SOM не передал срок
+}
+
+// TimeToRespond возвращает время до крайнего срока загрузки доказательств.
+// ok = false, если срока нет; отрицательное значение — срок пропущен, и спор проигран автоматически.
+func (e *ChargebackEvent) TimeToRespond(now time.Time) (remaining time.Duration, ok bool) {
+	if e.EvidenceDueDate == nil {
+		return 0, false
+	}
+	return e.EvidenceDueDate.Sub(now), true
+}
+
+// Overdue сообщает, что срок загрузки доказательств истёк.
+func (e *ChargebackEvent) Overdue(now time.Time) bool {
+	remaining, ok := e.TimeToRespond(now)
+	return ok && remaining <= 0
+}
+
+// UnmarshalEvent разбирает тело колбэка в структуру по полю event.
+// Подпись не проверяется — для этого есть Handler.Parse.
+func UnmarshalEvent(body []byte) (Event, error) {
+	var header struct {
+		Event string `json:"event"`
+	}
+	if err := json.Unmarshal(body, &header); err != nil {
+		return nil, fmt.Errorf("can't unmarshal callback: %w", err)
+	}
+
+	var event Event
+	kind, _, _ := strings.Cut(header.Event, ".")
+	switch kind {
+	case "payment":
+		event = new(PaymentEvent)
+	case "refund":
+		event = new(RefundEvent)
+	case "chargeback":
+		event = new(ChargebackEvent)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEvent, header.Event)
+	}
+
+	if err := json.Unmarshal(body, event); err != nil {
+		return nil, fmt.Errorf("can't unmarshal %s callback: %w", header.Event, err)
+	}
+	if payment, ok := event.(*PaymentEvent); ok {
+		payment.RespBody = body
+	}
+	return event, nil
+}
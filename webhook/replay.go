@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const defaultNonceTTL = 24 * time.Hour
+
+var (
+	ErrStaleEvent       = errors.New("webhook: event is outside of tolerance window")
+	ErrMissingTimestamp = errors.New("webhook: event has no signed event_date")
+	ErrReplayed         = errors.New("webhook: event has already been processed")
+)
+
+// NonceStore хранит подписи уже обработанных колбэков для защиты от повторного воспроизведения.
+type NonceStore interface {
+	Seen(ctx context.Context, nonce string) (bool, error)
+	Remember(ctx context.Context, nonce string, ttl time.Duration) error
+}
+
+// MemoryNonceStore — NonceStore в памяти процесса.
+type MemoryNonceStore struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time
+}
+
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{
+		nonces: make(map[string]time.Time),
+	}
+}
+
+func (m *MemoryNonceStore) Seen(_ context.Context, nonce string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expiresAt, ok := m.nonces[nonce]
+	if !ok {
+		return false, nil
+	}
+	if time.Now().After(expiresAt) {
+		delete(m.nonces, nonce)
+		return false, nil
+	}
+	return true, nil
+}
+
+func (m *MemoryNonceStore) Remember(_ context.Context, nonce string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	// попутно чистим протухшие записи, чтобы map не рос бесконечно
+	for key, expiresAt := range m.nonces {
+		if now.After(expiresAt) {
+			delete(m.nonces, key)
+		}
+	}
+	m.nonces[nonce] = now.Add(ttl)
+
+	return nil
+}
+
+// checkFreshness проверяет event_date колбэка, только если Signatures его подписывает:
+// неподписанное время можно переписать и воспроизвести перехваченный колбэк после истечения nonce.
+func (h *Handler) checkFreshness(eventDate time.Time, signed bool) error {
+	if h.Tolerance <= 0 || !signed {
+		return nil
+	}
+	if eventDate.IsZero() {
+		return ErrMissingTimestamp
+	}
+
+	age := time.Since(eventDate)
+	if age < 0 {
+		age = -age
+	}
+	if age > h.Tolerance {
+		return ErrStaleEvent
+	}
+	return nil
+}
+
+// checkNonce возвращает ErrReplayed, если колбэк с такой подписью уже обработан.
+func (h *Handler) checkNonce(ctx context.Context, nonce string) error {
+	if h.Nonces == nil {
+		return nil
+	}
+	seen, err := h.Nonces.Seen(ctx, nonce)
+	if err != nil {
+		return err
+	}
+	if seen {
+		return ErrReplayed
+	}
+	return nil
+}
+
+// nonceTTL покрывает всё окно приёма: колбэк принимается, пока event_date в пределах
+// ±Tolerance от текущего времени, то есть в течение 2*Tolerance.
+func (h *Handler) nonceTTL() time.Duration {
+	if h.Tolerance > 0 {
+		return 2 * h.Tolerance
+	}
+	return defaultNonceTTL
+}
@@ -0,0 +1,309 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	softline "github.com/dwnGnL/softlinePayment"
+)
+
+const (
+	EventPaymentSucceeded = "payment.succeeded"
+	EventPaymentFailed    = "payment.failed"
+	EventRefundCompleted  = "refund.completed"
+
+	DefaultSignatureHeader = "Signature"
+
+	maxBodySize = 1 << 20
+)
+
+var (
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+	ErrUnknownEvent     = errors.New("webhook: unknown event")
+
+	errSecretUnavailable = errors.New("webhook: can't resolve secret key")
+)
+
+// Verifier проверяет подпись колбэка. Ему удовлетворяет *softline.Service.
+type Verifier interface {
+	VerifySignature(signature string, params softline.Signature) bool
+}
+
+// Notifier получает окончательные статусы заказов. Ему удовлетворяет *softline.Service,
+// что позволяет CreatePaymentAsync узнавать о них из колбэков.
+type Notifier interface {
+	NotifyPaymentStatus(payment *softline.PaymentResp)
+}
+
+type PaymentSucceeded struct {
+	Payment *softline.PaymentResp
+}
+
+type PaymentFailed struct {
+	Payment *softline.PaymentResp
+	Reason  string
+}
+
+type RefundCompleted struct {
+	Payment *softline.PaymentResp
+}
+
+// ChargebackCreated — открыт chargeback; Chargeback.TimeToRespond показывает, сколько осталось до срока.
+type ChargebackCreated struct {
+	Chargeback *ChargebackEvent
+}
+
+// Handler принимает колбэки SOM, проверяет подпись и передаёт типизированные события обработчикам.
+type Handler struct {
+	verifier        Verifier
+	secretKey       string
+	SignatureHeader string
+	// допустимое расхождение event_date с текущим временем; 0 — не проверять. Действует, только
+	// если Signatures подписывает event_date (см. softline.NewSignatureBuilderWithEventDate)
+	Tolerance time.Duration
+	// хранилище обработанных подписей; nil — защита от повторов выключена
+	Nonces NonceStore
+	// хранилище обработанных событий; nil — дедупликация выключена
+	Dedup    DeduplicationStore
+	DedupTTL time.Duration
+	// набор подписываемых полей по типу события
+	Signatures *softline.SignatureBuilder
+	// получатель статусов для CreatePaymentAsync; nil — не уведомлять
+	Notifier Notifier
+	// шина событий SDK, получает EventWebhookReceived по каждому проверенному колбэку
+	Events *softline.EventBus
+	// источник ключа подписи (Credentials.SecretKey) вместо secretKey из NewHandler
+	Secrets softline.SecretProvider
+
+	onPaymentSucceeded []func(ctx context.Context, event PaymentSucceeded) error
+	onPaymentFailed    []func(ctx context.Context, event PaymentFailed) error
+	onRefundCompleted  []func(ctx context.Context, event RefundCompleted) error
+	onChargeback       []func(ctx context.Context, event ChargebackCreated) error
+}
+
+func NewHandler(verifier Verifier, secretKey string) *Handler {
+	return &Handler{
+		verifier:        verifier,
+		secretKey:       secretKey,
+		SignatureHeader: DefaultSignatureHeader,
+		Signatures:      softline.NewSignatureBuilder(secretKey),
+	}
+}
+
+func (h *Handler) OnPaymentSucceeded(fn func(ctx context.Context, event PaymentSucceeded) error) {
+	h.onPaymentSucceeded = append(h.onPaymentSucceeded, fn)
+}
+
+func (h *Handler) OnPaymentFailed(fn func(ctx context.Context, event PaymentFailed) error) {
+	h.onPaymentFailed = append(h.onPaymentFailed, fn)
+}
+
+func (h *Handler) OnRefundCompleted(fn func(ctx context.Context, event RefundCompleted) error) {
+	h.onRefundCompleted = append(h.onRefundCompleted, fn)
+}
+
+func (h *Handler) OnChargebackCreated(fn func(ctx context.Context, event ChargebackCreated) error) {
+	h.onChargeback = append(h.onChargeback, fn)
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "can't read body", http.StatusBadRequest)
+		return
+	}
+
+	payment, err := h.Parse(r.Context(), r.Header.Get(h.SignatureHeader), body)
+	switch {
+	case errors.Is(err, ErrInvalidSignature):
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	case errors.Is(err, ErrStaleEvent), errors.Is(err, ErrMissingTimestamp):
+		http.Error(w, "stale event", http.StatusBadRequest)
+		return
+	case errors.Is(err, errSecretUnavailable):
+		// 5xx заставит SOM повторить доставку
+		http.Error(w, "secret key unavailable", http.StatusInternalServerError)
+		return
+	case err != nil:
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	h.Events.Publish(softline.LifecycleEvent{Type: softline.EventWebhookReceived, Payment: payment})
+
+	switch err = h.checkNonce(r.Context(), payment.Signature); {
+	case errors.Is(err, ErrReplayed):
+		// повтор уже обработанного колбэка: подтверждаем, но не обрабатываем
+		w.WriteHeader(http.StatusOK)
+		return
+	case err != nil:
+		http.Error(w, "nonce store failed", http.StatusInternalServerError)
+		return
+	}
+
+	var eventID string
+	if h.Dedup != nil {
+		eventID = EventID(payment)
+		reserved, err := h.Dedup.Reserve(r.Context(), eventID, h.dedupTTL())
+		if err != nil {
+			http.Error(w, "deduplication store failed", http.StatusInternalServerError)
+			return
+		}
+		if !reserved {
+			// событие уже обработано или обрабатывается параллельно
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+	}
+
+	if err = h.Dispatch(r.Context(), payment); err != nil && !errors.Is(err, ErrUnknownEvent) {
+		if h.Dedup != nil {
+			_ = h.Dedup.Release(r.Context(), eventID)
+		}
+		// 5xx заставит SOM повторить доставку
+		http.Error(w, "handler failed", http.StatusInternalServerError)
+		return
+	}
+
+	if h.Nonces != nil {
+		// запоминаем только после успешной обработки, чтобы повтор SOM после ошибки не потерялся
+		if err = h.Nonces.Remember(r.Context(), payment.Signature, h.nonceTTL()); err != nil {
+			http.Error(w, "nonce store failed", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Parse разбирает тело колбэка, проверяет его подпись и, если задан Tolerance и event_date подписан, свежесть.
+// ctx ограничивает обращение к Secrets.
+func (h *Handler) Parse(ctx context.Context, signature string, body []byte) (*softline.PaymentResp, error) {
+	payment := new(softline.PaymentResp)
+	if err := json.Unmarshal(body, payment); err != nil {
+		return nil, fmt.Errorf("can't unmarshal callback: %w", err)
+	}
+
+	// дата создания подписывается в том виде, в каком пришла, поэтому берём её строкой
+	var raw struct {
+		CreateDate string `json:"create_date"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("can't unmarshal callback: %w", err)
+	}
+
+	payment.Signature = signature
+	payment.RespBody = body
+
+	secretKey, err := h.currentSecretKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := h.Signatures
+	if builder == nil {
+		builder = softline.NewSignatureBuilder(secretKey)
+	} else if secretKey != h.secretKey {
+		custom := *builder
+		custom.SecretKey = secretKey
+		builder = &custom
+	}
+	params, err := builder.Build(body)
+	signedDate := err == nil && builder.Signs(payment.Event, "event_date")
+	if errors.Is(err, softline.ErrUnsupportedSignatureEvent) {
+		// неизвестный тип события подписывается полями платежа
+		params, err = softline.Signature{
+			SecretKey:     secretKey,
+			Event:         payment.Event,
+			OrderID:       strconv.FormatInt(payment.OrderId, 10),
+			CreateDate:    raw.CreateDate,
+			PaymentMethod: payment.Payment.Method,
+			Currency:      payment.Currency,
+			CustomerEmail: payment.Customer.Email,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !h.verifier.VerifySignature(signature, params) {
+		return nil, ErrInvalidSignature
+	}
+	if err = h.checkFreshness(payment.EventDate, signedDate); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// currentSecretKey возвращает ключ подписи из Secrets, если он задан, иначе ключ из NewHandler.
+func (h *Handler) currentSecretKey(ctx context.Context) (string, error) {
+	if h.Secrets == nil {
+		return h.secretKey, nil
+	}
+	credentials, err := h.Secrets.Credentials(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errSecretUnavailable, err)
+	}
+	if credentials.SecretKey == "" {
+		return h.secretKey, nil
+	}
+	return credentials.SecretKey, nil
+}
+
+// Dispatch передаёт колбэк обработчикам, зарегистрированным на его тип события.
+func (h *Handler) Dispatch(ctx context.Context, payment *softline.PaymentResp) error {
+	if h.Notifier != nil {
+		h.Notifier.NotifyPaymentStatus(payment)
+	}
+
+	switch payment.Event {
+	case EventPaymentSucceeded:
+		for _, fn := range h.onPaymentSucceeded {
+			if err := fn(ctx, PaymentSucceeded{Payment: payment}); err != nil {
+				return err
+			}
+		}
+	case EventPaymentFailed:
+		event := PaymentFailed{
+			Payment: payment,
+			Reason:  payment.Payment.ErrorDescription,
+		}
+		for _, fn := range h.onPaymentFailed {
+			if err := fn(ctx, event); err != nil {
+				return err
+			}
+		}
+	case EventRefundCompleted:
+		for _, fn := range h.onRefundCompleted {
+			if err := fn(ctx, RefundCompleted{Payment: payment}); err != nil {
+				return err
+			}
+		}
+	case EventChargebackCreated:
+		chargeback := new(ChargebackEvent)
+		if err := json.Unmarshal(payment.RespBody, chargeback); err != nil {
+			return fmt.Errorf("can't unmarshal %s callback: %w", payment.Event, err)
+		}
+		for _, fn := range h.onChargeback {
+			if err := fn(ctx, ChargebackCreated{Chargeback: chargeback}); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownEvent, payment.Event)
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	softline "github.com/dwnGnL/softlinePayment"
+)
+
+const testSecret = "secret"
+
+func newTestService(t *testing.T) *softline.Service {
+	t.Helper()
+
+	s, err := softline.New(&softline.Config{URI: "http://127.0.0.1:1", Login: "login", Pass: "pass"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return s
+}
+
+func paymentBody(eventDate time.Time) []byte {
+	return []byte(fmt.Sprintf(`{"event":"payment.succeeded","event_date":%q,"order_id":9007199254740993,`+
+		`"create_date":"2024-01-02T03:04:05Z","currency":"RUB","status":"paid",`+
+		`"customer":{"email":"customer@example.com"},"payment":{"payment_method":"card"}}`,
+		eventDate.UTC().Format(time.RFC3339)))
+}
+
+// somSignature подписывает колбэк так же, как SOM: secret;event;order_id;create_date;payment_method;currency;email.
+func somSignature(s *softline.Service) string {
+	return s.GenerateSignature(softline.Signature{
+		SecretKey:     testSecret,
+		Event:         "payment.succeeded",
+		OrderID:       "9007199254740993",
+		CreateDate:    "2024-01-02T03:04:05Z",
+		PaymentMethod: "card",
+		Currency:      "RUB",
+		CustomerEmail: "customer@example.com",
+	})
+}
+
+func signBody(t *testing.T, s *softline.Service, builder *softline.SignatureBuilder, body []byte) string {
+	t.Helper()
+
+	params, err := builder.Build(body)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	return s.GenerateSignature(params)
+}
+
+func TestParseDefaultSignature(t *testing.T) {
+	s := newTestService(t)
+	h := NewHandler(s, testSecret)
+
+	payment, err := h.Parse(context.Background(), somSignature(s), paymentBody(time.Now()))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if payment.OrderId != 9007199254740993 {
+		t.Fatalf("order_id = %d", payment.OrderId)
+	}
+
+	if _, err = h.Parse(context.Background(), strings.Repeat("0", 128), paymentBody(time.Now())); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("Parse with wrong signature: %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+func TestToleranceWithoutSignedEventDate(t *testing.T) {
+	s := newTestService(t)
+	h := NewHandler(s, testSecret)
+	h.Tolerance = time.Minute
+
+	// event_date не подписан по умолчанию, поэтому на него не опираемся
+	if _, err := h.Parse(context.Background(), somSignature(s), paymentBody(time.Now().Add(-time.Hour))); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+}
+
+func TestToleranceWithSignedEventDate(t *testing.T) {
+	s := newTestService(t)
+	h := NewHandler(s, testSecret)
+	h.Signatures = softline.NewSignatureBuilderWithEventDate(testSecret)
+	h.Tolerance = time.Minute
+
+	fresh := paymentBody(time.Now())
+	if _, err := h.Parse(context.Background(), signBody(t, s, h.Signatures, fresh), fresh); err != nil {
+		t.Fatalf("Parse fresh: %v", err)
+	}
+
+	stale := paymentBody(time.Now().Add(-time.Hour))
+	if _, err := h.Parse(context.Background(), signBody(t, s, h.Signatures, stale), stale); !errors.Is(err, ErrStaleEvent) {
+		t.Fatalf("Parse stale: %v, want %v", err, ErrStaleEvent)
+	}
+
+	// переписанный event_date ломает подпись
+	signature := signBody(t, s, h.Signatures, stale)
+	if _, err := h.Parse(context.Background(), signature, fresh); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("Parse rewritten event_date: %v, want %v", err, ErrInvalidSignature)
+	}
+
+	noDate := []byte(strings.Replace(string(fresh), `"event_date":`, `"event_date_":`, 1))
+	if _, err := h.Parse(context.Background(), signBody(t, s, h.Signatures, noDate), noDate); !errors.Is(err, ErrMissingTimestamp) {
+		t.Fatalf("Parse without event_date: %v, want %v", err, ErrMissingTimestamp)
+	}
+}
+
+func TestServeHTTPReplay(t *testing.T) {
+	s := newTestService(t)
+	h := NewHandler(s, testSecret)
+	h.Nonces = NewMemoryNonceStore()
+
+	calls := 0
+	h.OnPaymentSucceeded(func(context.Context, PaymentSucceeded) error {
+		calls++
+		return nil
+	})
+
+	signature := somSignature(s)
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(paymentBody(time.Now())))
+		req.Header.Set(DefaultSignatureHeader, signature)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("delivery %d: status %d", i, rec.Code)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("handler calls = %d, want 1", calls)
+	}
+
+	if err := h.checkNonce(context.Background(), signature); !errors.Is(err, ErrReplayed) {
+		t.Fatalf("checkNonce: %v, want %v", err, ErrReplayed)
+	}
+}
+
+func TestServeHTTPInvalidSignature(t *testing.T) {
+	s := newTestService(t)
+	h := NewHandler(s, testSecret)
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(paymentBody(time.Now())))
+	req.Header.Set(DefaultSignatureHeader, "bad")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestServeHTTPRetryAfterHandlerError(t *testing.T) {
+	s := newTestService(t)
+	h := NewHandler(s, testSecret)
+	h.Nonces = NewMemoryNonceStore()
+
+	fail := true
+	h.OnPaymentSucceeded(func(context.Context, PaymentSucceeded) error {
+		if fail {
+			fail = false
+			return errors.New("temporary")
+		}
+		return nil
+	})
+
+	signature := somSignature(s)
+	for _, want := range []int{http.StatusInternalServerError, http.StatusOK} {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(paymentBody(time.Now())))
+		req.Header.Set(DefaultSignatureHeader, signature)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Fatalf("status %d, want %d", rec.Code, want)
+		}
+	}
+}